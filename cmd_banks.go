@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rasulov-emirlan/dupay/pkg/dupay"
+)
+
+func runBanks(ctx context.Context, args []string) error {
+	fs := newFlagSet("banks", "dupay banks [options]")
+	var definitions parserDefinitions
+	definitions.registerFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	parsers, err := definitions.load()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Supported banks:")
+	for _, p := range parsers {
+		fmt.Printf("\n  %s (-bank file.pdf=%s)\n", p.BankName(), p.ID())
+		fmt.Println("    Detection markers (score added when the text contains it):")
+		for _, m := range p.Markers() {
+			fmt.Printf("      %+4d  %q\n", m.Weight, m.Text)
+		}
+	}
+
+	fmt.Printf("\nEach file is parsed by the bank with the highest score (at most %d).\n", dupay.MaxScore)
+	fmt.Println("Files where two banks share the highest score are skipped unless -bank is given.")
+	return nil
+}
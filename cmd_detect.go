@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/rasulov-emirlan/dupay/pkg/dupay"
+)
+
+// reportWriters maps the detect -format values to report writers.
+var reportWriters = map[string]func(io.Writer, *dupay.Report) error{
+	"text": dupay.WriteText,
+	"json": dupay.WriteJSON,
+	"csv":  dupay.WriteCSV,
+}
+
+func runDetect(ctx context.Context, args []string) error {
+	fs := newFlagSet("detect", "dupay detect [options] <pdf1> <pdf2> [pdf3...]",
+		"dupay detect optima.pdf mbank.pdf",
+		"dupay detect -time 2m -amount 5 optima.pdf mbank.pdf",
+		"dupay detect -format json optima.pdf mbank.pdf > report.json",
+		"dupay detect -transfer-time 1h optima.pdf mbank.pdf",
+		"dupay detect -rates nbkr.csv optima_usd.pdf mbank.pdf",
+	)
+	opts := dupay.DefaultOptions()
+	fs.DurationVar(&opts.MaxTimeDiff, "time", opts.MaxTimeDiff, "Maximum time difference between transactions (e.g., 1m, 2m)")
+	fs.Var((*amountFlag)(&opts.MaxAmountDiff), "amount", "Maximum amount difference, in the currency of the compared transactions")
+	fs.BoolVar(&opts.DetectTransfers, "transfers", opts.DetectTransfers, "Detect transfers between your own accounts and exclude them from duplicates")
+	fs.DurationVar(&opts.MaxTransferTimeDiff, "transfer-time", opts.MaxTransferTimeDiff, "Maximum time between the debit and credit of a transfer")
+	fs.Var((*amountFlag)(&opts.MaxTransferAmountDiff), "transfer-amount", "Maximum amount difference between the debit and credit of a transfer")
+	fs.BoolVar(&opts.CompareGross, "gross", opts.CompareGross, "Compare amounts including bank fees instead of the operation amounts")
+	fs.BoolVar(&opts.DetectDoubleCharges, "same-bank", opts.DetectDoubleCharges, "Also report the same payment charged twice within one statement")
+	fs.BoolVar(&opts.OneToOne, "one-to-one", opts.OneToOne, "Pair each debit with at most one counterpart per account, keeping the closest pairs")
+	fs.BoolVar(&opts.SameMerchant, "same-merchant", opts.SameMerchant, "Only pair debits whose descriptions name the same merchant")
+	fs.IntVar(&opts.MinConfidence, "min-confidence", opts.MinConfidence, "Only report duplicates with at least this confidence, from 0 to 100")
+	ratesFile := fs.String("rates", "", "Exchange-rate `file` (.csv or .json) for comparing debits in different currencies")
+	fs.Float64Var(&opts.MaxRateDiffPercent, "rate-tolerance", opts.MaxRateDiffPercent, "Maximum difference between converted amounts, in percent")
+	format := fs.String("format", "text", "Output format: text, json or csv")
+	loader := newLoader()
+	loader.registerFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	writeReport, ok := reportWriters[*format]
+	if !ok {
+		return fmt.Errorf("unknown output format %q", *format)
+	}
+
+	if opts.MaxRateDiffPercent < 0 {
+		return fmt.Errorf("-rate-tolerance must not be negative")
+	}
+	if opts.MinConfidence < 0 || opts.MinConfidence > 100 {
+		return fmt.Errorf("-min-confidence must be between 0 and 100")
+	}
+
+	pdfFiles, err := requireFiles(fs, 2)
+	if err != nil {
+		return err
+	}
+
+	if *ratesFile != "" {
+		opts.Rates, err = dupay.LoadExchangeRates(*ratesFile)
+		if err != nil {
+			return fmt.Errorf("loading exchange rates: %w", err)
+		}
+	}
+
+	statements, err := loader.load(ctx, pdfFiles)
+	if err != nil {
+		return err
+	}
+	report := &dupay.Report{
+		Files:   fileResults(statements),
+		Options: opts,
+	}
+
+	// CSV has no place for per-file errors, so surface them separately
+	if *format == "csv" {
+		printFileErrors(report.Files)
+	}
+
+	// Find transfers and duplicates
+	result := dupay.Analyze(allTransactions(statements), opts)
+	report.Duplicates = result.Duplicates
+	report.Transfers = result.Transfers
+
+	if err := writeReport(os.Stdout, report); err != nil {
+		return fmt.Errorf("writing report: %w", err)
+	}
+	return nil
+}
+
+// printFileErrors reports files that could not be processed on stderr.
+func printFileErrors(results []dupay.FileResult) {
+	for _, f := range results {
+		if f.Err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", f.Path, f.Err)
+		}
+	}
+}
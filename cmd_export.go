@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/rasulov-emirlan/dupay/pkg/dupay"
+)
+
+// transactionWriters maps the export -format values to transaction writers.
+var transactionWriters = map[string]func(io.Writer, []dupay.Transaction) error{
+	"csv":  dupay.WriteTransactionsCSV,
+	"json": dupay.WriteTransactionsJSON,
+}
+
+func runExport(ctx context.Context, args []string) error {
+	fs := newFlagSet("export", "dupay export [options] <pdf1> [pdf2...]",
+		"dupay export optima.pdf mbank.pdf > transactions.csv",
+		"dupay export -format json -o transactions.json optima.pdf",
+	)
+	format := fs.String("format", "csv", "Output format: csv or json")
+	output := fs.String("o", "", "Write to this file instead of stdout")
+	loader := newLoader()
+	loader.registerFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	writeTransactions, ok := transactionWriters[*format]
+	if !ok {
+		return fmt.Errorf("unknown output format %q", *format)
+	}
+
+	pdfFiles, err := requireFiles(fs, 1)
+	if err != nil {
+		return err
+	}
+
+	statements, err := loader.load(ctx, pdfFiles)
+	if err != nil {
+		return err
+	}
+	printFileErrors(fileResults(statements))
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if err := writeTransactions(w, allTransactions(statements)); err != nil {
+		return fmt.Errorf("writing transactions: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/rasulov-emirlan/dupay/pkg/dupay"
+)
+
+func runParse(ctx context.Context, args []string) error {
+	fs := newFlagSet("parse", "dupay parse [options] <pdf1> [pdf2...]",
+		"dupay parse mbank.pdf",
+		"dupay parse -diagnose optima.pdf",
+	)
+	diagnose := fs.Bool("diagnose", false, "Report how many lines were consumed, skipped or rejected, and why, instead of listing transactions")
+	loader := newLoader()
+	loader.registerFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	pdfFiles, err := requireFiles(fs, 1)
+	if err != nil {
+		return err
+	}
+
+	statements, err := loader.load(ctx, pdfFiles)
+	if err != nil {
+		return err
+	}
+
+	total := 0
+	for i, s := range statements {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s\n", filepath.Base(s.Result.Path))
+		if s.Result.Err != nil {
+			fmt.Printf("  Error: %v\n", s.Result.Err)
+			continue
+		}
+		fmt.Printf("  Detected: %s, %d transactions\n", s.Result.Bank, s.Result.Transactions)
+		printStatement(s.Result)
+		if *diagnose {
+			printDiagnostics(s.Result)
+			total += len(s.Transactions)
+			continue
+		}
+		fmt.Println()
+		if err := dupay.WriteTransactionsText(os.Stdout, s.Transactions); err != nil {
+			return err
+		}
+		total += len(s.Transactions)
+	}
+
+	fmt.Printf("\nTotal transactions: %d\n", total)
+	return nil
+}
+
+// printStatement prints the account, period and totals a statement declares,
+// and whether the parsed transactions add up to them.
+func printStatement(f dupay.FileResult) {
+	s := f.Statement
+	if s.Account != "" {
+		fmt.Printf("  Account: %s %s\n", s.Account, s.Currency)
+	}
+	if s.Holder != "" {
+		fmt.Printf("  Holder: %s\n", s.Holder)
+	}
+	if !s.PeriodStart.IsZero() {
+		fmt.Printf("  Period: %s - %s\n", s.PeriodStart.Format("02.01.2006"), s.PeriodEnd.Format("02.01.2006"))
+	}
+	if !s.GeneratedAt.IsZero() {
+		fmt.Printf("  Generated: %s\n", s.GeneratedAt.Format("02.01.2006 15:04"))
+	}
+
+	t := s.Totals
+	if t.IsZero() {
+		return
+	}
+	if t.OpeningBalance.Currency != "" || t.ClosingBalance.Currency != "" {
+		fmt.Printf("  Balance: %s -> %s\n", formatDeclared(t.OpeningBalance), formatDeclared(t.ClosingBalance))
+	}
+	if t.TotalDebits.Currency != "" || t.TotalCredits.Currency != "" {
+		fmt.Printf("  Totals: debits %s, credits %s\n", formatDeclared(t.TotalDebits), formatDeclared(t.TotalCredits))
+	}
+	switch {
+	case f.TotalsErr != nil:
+		fmt.Printf("  Warning: %v\n", f.TotalsErr)
+	case t.Verifiable():
+		fmt.Printf("  Parsed transactions match the declared totals\n")
+	}
+}
+
+// formatDeclared formats a declared figure, or "?" when the statement
+// doesn't declare it.
+func formatDeclared(m dupay.Money) string {
+	if m.Currency == "" {
+		return "?"
+	}
+	return m.String()
+}
+
+// printDiagnostics prints how the parser used a file's lines and every
+// operation it had to drop.
+func printDiagnostics(f dupay.FileResult) {
+	fmt.Printf("  Lines: %d consumed, %d skipped, %d rejected (of %d non-empty)\n",
+		f.Lines.Consumed, f.Lines.Skipped, f.Lines.Rejected, f.Lines.Total())
+	if len(f.Warnings) == 0 {
+		return
+	}
+	fmt.Printf("  Warnings:\n")
+	for _, w := range f.Warnings {
+		fmt.Printf("    %s\n", w)
+	}
+}
@@ -2,4 +2,9 @@ module github.com/rasulov-emirlan/dupay
 
 go 1.25.4
 
-require github.com/ledongthuc/pdf v0.0.0-20250511090121-5959a4027728
+require (
+	github.com/ledongthuc/pdf v0.0.0-20250511090121-5959a4027728
+	golang.org/x/term v0.45.0
+)
+
+require golang.org/x/sys v0.47.0 // indirect
@@ -1,155 +1,174 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
-	"path/filepath"
+	"os/signal"
 	"strings"
-	"time"
-
-	"github.com/ledongthuc/pdf"
 )
 
 // Version is set at build time via -ldflags
 var Version = "dev"
 
+// errUsage is returned by commands after they have printed their usage
+// because of invalid arguments.
+var errUsage = errors.New("invalid usage")
+
+// command is a dupay subcommand with its own flag set.
+type command struct {
+	name    string
+	summary string
+	run     func(ctx context.Context, args []string) error
+}
+
+// commands lists the subcommands in the order they are shown in help.
+var commands = []command{
+	{name: "detect", summary: "Find potential duplicate payments across statements", run: runDetect},
+	{name: "parse", summary: "Extract and print transactions from statements", run: runParse},
+	{name: "export", summary: "Convert statements to CSV or JSON", run: runExport},
+	{name: "banks", summary: "List supported banks and how they are detected", run: runBanks},
+	{name: "version", summary: "Print version information", run: runVersion},
+}
+
 func main() {
-	// CLI flags
-	maxTimeDiff := flag.Duration("time", time.Minute, "Maximum time difference between transactions (e.g., 1m, 2m)")
-	maxAmountDiff := flag.Float64("amount", 1.0, "Maximum amount difference in KGS")
-	showVersion := flag.Bool("version", false, "Print version information")
-	flag.Parse()
-
-	if *showVersion {
-		fmt.Printf("dupay version %s\n", Version)
-		os.Exit(0)
+	args := os.Args[1:]
+	if len(args) > 0 && isHelp(args[0]) {
+		printUsage()
+		return
 	}
 
-	// Get PDF files from arguments
-	pdfFiles := flag.Args()
-	if len(pdfFiles) < 2 {
-		fmt.Println("Usage: dupay [options] <pdf1> <pdf2> [pdf3...]")
-		fmt.Println("\nOptions:")
-		flag.PrintDefaults()
-		fmt.Println("\nExample:")
-		fmt.Println("  dupay -time 1m -amount 1 optima.pdf mbank.pdf")
+	cmd, args := selectCommand(args)
+	if cmd == nil {
+		printUsage()
 		os.Exit(1)
 	}
 
-	// Register all available parsers
-	parsers := []BankParser{
-		NewOptimaParser(),
-		NewMbankParser(),
+	// A Ctrl-C during a password prompt would otherwise exit with echo off
+	restoreTerminal := saveTerminal()
+
+	// The first Ctrl-C cancels the command; a second one kills the process
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	err := cmd.run(ctx, args)
+	stop()
+	switch {
+	case err == nil:
+	case errors.Is(err, context.Canceled):
+		restoreTerminal()
+		fmt.Fprintln(os.Stderr, "\nInterrupted")
+		os.Exit(130)
+	case errors.Is(err, errUsage):
+		os.Exit(1)
+	default:
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
 	}
+}
 
-	// Parse all PDFs
-	var allTransactions []Transaction
-
-	for _, pdfFile := range pdfFiles {
-		fmt.Printf("Processing: %s\n", filepath.Base(pdfFile))
-
-		// Extract text from PDF
-		content, err := extractPDFText(pdfFile)
-		if err != nil {
-			fmt.Printf("  Error reading PDF: %v\n", err)
-			continue
-		}
-
-		// Find matching parser
-		var matchedParser BankParser
-		for _, parser := range parsers {
-			if parser.CanParse(content) {
-				matchedParser = parser
-				break
-			}
-		}
-
-		if matchedParser == nil {
-			fmt.Printf("  Warning: No parser found for this PDF format\n")
-			continue
-		}
-
-		fmt.Printf("  Detected: %s\n", matchedParser.BankName())
-
-		// Parse transactions
-		transactions, err := matchedParser.Parse(content)
-		if err != nil {
-			fmt.Printf("  Error parsing: %v\n", err)
-			continue
-		}
-
-		fmt.Printf("  Found %d transactions\n", len(transactions))
-		allTransactions = append(allTransactions, transactions...)
+// selectCommand picks the subcommand named by the first argument. Invocations
+// without a subcommand, like "dupay -time 2m a.pdf b.pdf", run detect so
+// existing scripts keep working.
+func selectCommand(args []string) (*command, []string) {
+	if len(args) == 0 {
+		return nil, nil
 	}
 
-	fmt.Printf("\nTotal transactions: %d\n", len(allTransactions))
-	fmt.Printf("Looking for duplicates (time diff <= %v, amount diff <= %.2f KGS)...\n\n", *maxTimeDiff, *maxAmountDiff)
-
-	// Find duplicates
-	duplicates := FindDuplicates(allTransactions, *maxTimeDiff, *maxAmountDiff)
-
-	if len(duplicates) == 0 {
-		fmt.Println("No potential duplicates found.")
-		return
+	if cmd := findCommand(args[0]); cmd != nil {
+		return cmd, args[1:]
 	}
 
-	fmt.Printf("Found %d potential duplicate(s):\n\n", len(duplicates))
+	if args[0] == "-version" || args[0] == "--version" {
+		return findCommand("version"), args[1:]
+	}
 
-	for i, dup := range duplicates {
-		fmt.Printf("=== Duplicate #%d ===\n", i+1)
-		fmt.Printf("Time difference: %v\n", dup.TimeDiff)
-		fmt.Printf("Amount difference: %.2f KGS\n\n", dup.AmountDiff)
+	if strings.HasPrefix(args[0], "-") {
+		return findCommand("detect"), args
+	}
+	if _, err := os.Stat(args[0]); err == nil {
+		return findCommand("detect"), args
+	}
 
-		fmt.Printf("Transaction 1 (%s):\n", dup.Transaction1.Bank)
-		fmt.Printf("  Date/Time: %s\n", dup.Transaction1.DateTime.Format("02.01.2006 15:04"))
-		fmt.Printf("  Amount: %.2f %s\n", dup.Transaction1.Amount, dup.Transaction1.Currency)
-		fmt.Printf("  Description: %s\n\n", truncateString(dup.Transaction1.Description, 80))
+	fmt.Fprintf(os.Stderr, "Unknown command %q\n\n", args[0])
+	return nil, nil
+}
 
-		fmt.Printf("Transaction 2 (%s):\n", dup.Transaction2.Bank)
-		fmt.Printf("  Date/Time: %s\n", dup.Transaction2.DateTime.Format("02.01.2006 15:04"))
-		fmt.Printf("  Amount: %.2f %s\n", dup.Transaction2.Amount, dup.Transaction2.Currency)
-		fmt.Printf("  Description: %s\n", truncateString(dup.Transaction2.Description, 80))
-		fmt.Println(strings.Repeat("-", 60))
+func isHelp(arg string) bool {
+	switch arg {
+	case "help", "-h", "-help", "--help":
+		return true
 	}
+	return false
+}
 
-	// Summary
-	var totalDuplicateAmount float64
-	for _, dup := range duplicates {
-		// Use the average of both amounts
-		totalDuplicateAmount += (dup.Transaction1.Amount + dup.Transaction2.Amount) / 2
+func findCommand(name string) *command {
+	for i := range commands {
+		if commands[i].name == name {
+			return &commands[i]
+		}
 	}
-	fmt.Printf("\nTotal potential duplicate amount: %.2f KGS\n", totalDuplicateAmount)
+	return nil
 }
 
-// extractPDFText extracts all text content from a PDF file
-func extractPDFText(path string) (string, error) {
-	f, r, err := pdf.Open(path)
-	if err != nil {
-		return "", err
+func printUsage() {
+	fmt.Println("Usage: dupay <command> [options] [pdf...]")
+	fmt.Println("\nCommands:")
+	for _, c := range commands {
+		fmt.Printf("  %-8s %s\n", c.name, c.summary)
 	}
-	defer f.Close()
+	fmt.Println("\nRun 'dupay <command> -h' for the options of a command.")
+	fmt.Println("Running dupay without a command is the same as 'dupay detect'.")
+}
 
-	var buf strings.Builder
-	for i := 1; i <= r.NumPage(); i++ {
-		p := r.Page(i)
-		if p.V.IsNull() {
-			continue
+// newFlagSet creates a flag set for a subcommand whose help prints the given
+// usage line, the command's options and examples.
+func newFlagSet(name, usage string, examples ...string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.Usage = func() {
+		out := fs.Output()
+		fmt.Fprintf(out, "Usage: %s\n", usage)
+		if hasFlags(fs) {
+			fmt.Fprintln(out, "\nOptions:")
+			fs.PrintDefaults()
 		}
-		text, err := p.GetPlainText(nil)
-		if err != nil {
-			continue
+		if len(examples) > 0 {
+			fmt.Fprintln(out, "\nExamples:")
+			for _, e := range examples {
+				fmt.Fprintf(out, "  %s\n", e)
+			}
 		}
-		buf.WriteString(text)
-		buf.WriteString("\n")
 	}
+	return fs
+}
+
+func hasFlags(fs *flag.FlagSet) bool {
+	found := false
+	fs.VisitAll(func(*flag.Flag) { found = true })
+	return found
+}
 
-	return buf.String(), nil
+// requireFiles prints the usage and returns errUsage when fewer than min
+// files were given.
+func requireFiles(fs *flag.FlagSet, min int) ([]string, error) {
+	files := fs.Args()
+	if len(files) < min {
+		fs.Usage()
+		return nil, errUsage
+	}
+	return files, nil
 }
 
-func truncateString(s string, maxLen int) string {
-	if len(s) <= maxLen {
-		return s
+func runVersion(ctx context.Context, args []string) error {
+	fs := newFlagSet("version", "dupay version")
+	if err := fs.Parse(args); err != nil {
+		return err
 	}
-	return s[:maxLen-3] + "..."
+	fmt.Printf("dupay version %s\n", Version)
+	return nil
 }
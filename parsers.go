@@ -0,0 +1,64 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/rasulov-emirlan/dupay/pkg/dupay"
+)
+
+// parserDefinitions supplies the bank parsers: the built-in ones and those
+// described by JSON definition files in a directory.
+type parserDefinitions struct {
+	// dir is the directory of definition files given with -parsers. When it
+	// is empty, the default directory is used if it exists.
+	dir string
+}
+
+func (d *parserDefinitions) registerFlags(fs *flag.FlagSet) {
+	fs.StringVar(&d.dir, "parsers", "", "`dir`ectory of JSON bank parser definitions (default "+defaultParsersDir()+")")
+}
+
+// defaultParsersDir returns the directory definitions are read from when
+// -parsers isn't given, or "" when there is no user config directory.
+func defaultParsersDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "dupay", "parsers")
+}
+
+// load returns the built-in parsers followed by the defined ones. A missing
+// default directory just means there are no definitions, but a missing
+// -parsers directory is an error.
+func (d *parserDefinitions) load() ([]dupay.BankParser, error) {
+	parsers := dupay.DefaultParsers()
+
+	dir := d.dir
+	if dir == "" {
+		dir = defaultParsersDir()
+		if dir == "" {
+			return parsers, nil
+		}
+		if _, err := os.Stat(dir); errors.Is(err, fs.ErrNotExist) {
+			return parsers, nil
+		}
+	}
+
+	defined, err := dupay.LoadParserDefinitions(dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading parser definitions: %w", err)
+	}
+	for _, p := range defined {
+		if dupay.ParserByID(parsers, p.ID()) != nil {
+			return nil, fmt.Errorf("reading parser definitions: parser %q is already defined", p.ID())
+		}
+		parsers = append(parsers, p)
+	}
+	return parsers, nil
+}
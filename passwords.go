@@ -0,0 +1,68 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/rasulov-emirlan/dupay/pkg/dupay"
+	"golang.org/x/term"
+)
+
+// passwords supplies the passwords tried on encrypted statements from the
+// command-line flags, prompting on the terminal for the rest.
+type passwords struct {
+	dupay.Passwords
+	// file is the path of a JSON file mapping file names to passwords.
+	file string
+}
+
+func newPasswords() *passwords {
+	p := &passwords{}
+	// Without a terminal, scripts fail instead of hanging on a prompt
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		p.Prompt = promptPassword
+	}
+	return p
+}
+
+func (p *passwords) registerFlags(fs *flag.FlagSet) {
+	fs.StringVar(&p.Password, "password", "", "Password for encrypted statements")
+	fs.StringVar(&p.file, "password-file", "", "JSON `file` mapping statement file names or patterns to passwords")
+}
+
+// load reads the password file, if one was given.
+func (p *passwords) load() error {
+	if p.file == "" {
+		return nil
+	}
+	byFile, err := dupay.ReadPasswordFile(p.file)
+	if err != nil {
+		return fmt.Errorf("reading password file: %w", err)
+	}
+	p.ByFile = byFile
+	return nil
+}
+
+// promptPassword asks for the password of path on the terminal without
+// echoing it.
+func promptPassword(path string) (string, error) {
+	fmt.Fprintf(os.Stderr, "Password for %s: ", path)
+	pw, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	return string(pw), err
+}
+
+// saveTerminal records the state of the terminal on stdin and returns a
+// function that restores it, so exiting on Ctrl-C during a password prompt
+// doesn't leave echo disabled. It does nothing when stdin is not a terminal.
+func saveTerminal() (restore func()) {
+	fd := int(os.Stdin.Fd())
+	state, err := term.GetState(fd)
+	if err != nil {
+		return func() {}
+	}
+	return func() {
+		term.Restore(fd, state)
+	}
+}
@@ -0,0 +1,110 @@
+package dupay
+
+import (
+	"time"
+)
+
+// Options configures Analyze.
+type Options struct {
+	// MaxTimeDiff is the maximum time between two charges of a duplicate.
+	MaxTimeDiff time.Duration
+	// MaxAmountDiff is the maximum amount difference of a duplicate, in minor
+	// units.
+	MaxAmountDiff int64
+	// DetectTransfers enables pairing debits with credits in another bank as
+	// internal transfers. Transfer debits are not considered duplicates.
+	DetectTransfers bool
+	// MaxTransferTimeDiff is the maximum time between a transfer's debit and credit.
+	MaxTransferTimeDiff time.Duration
+	// MaxTransferAmountDiff is the maximum difference, in minor units, between
+	// a transfer's debit and credit. It is 0 by default, so only a credit of
+	// the exact amount takes a debit out of the duplicate search.
+	MaxTransferAmountDiff int64
+	// DetectDoubleCharges also reports the same payment charged twice within
+	// one statement file. It relies on Transaction.Source.File: identical rows
+	// in different files are treated as overlapping statements and collapsed,
+	// while identical rows within one file are kept and matched.
+	DetectDoubleCharges bool
+	// CompareGross compares debits including their fees (Transaction.Gross)
+	// instead of the operation amounts alone.
+	CompareGross bool
+	// Rates, when set, lets debits that share no currency be compared by
+	// converting both into the rates' base currency.
+	Rates *ExchangeRates
+	// MaxRateDiffPercent is the maximum difference between converted amounts,
+	// as a percentage of the larger one. It allows for the spread between the
+	// official rates and the rates the banks actually charged.
+	MaxRateDiffPercent float64
+	// OneToOne pairs every debit with at most one counterpart in each other
+	// account. When a debit matches several, the set of pairs with the
+	// closest times, amounts and descriptions overall is kept, so the report
+	// lists distinct suspected duplicates instead of every combination.
+	OneToOne bool
+	// MinConfidence drops duplicate pairs with a lower DuplicateMatch.Confidence.
+	MinConfidence int
+	// SameMerchant only pairs debits whose merchants are the same or nearly
+	// so, by MerchantSimilarity. Debits without a known merchant are never
+	// paired.
+	SameMerchant bool
+}
+
+// DefaultOptions returns the options used by the dupay command by default.
+func DefaultOptions() Options {
+	return Options{
+		MaxTimeDiff:         time.Minute,
+		MaxAmountDiff:       100,
+		DetectTransfers:     true,
+		MaxTransferTimeDiff: 10 * time.Minute,
+		MaxRateDiffPercent:  2,
+	}
+}
+
+// Result holds everything Analyze found.
+type Result struct {
+	// Duplicates holds the potential duplicate payments, pair by pair, the
+	// most confident first.
+	Duplicates []DuplicateMatch
+	// Groups merges the duplicate pairs into one group per payment.
+	Groups []DuplicateGroup
+	// Transfers holds the internal transfers, if transfer detection is enabled.
+	Transfers []TransferMatch
+}
+
+// Analyze looks for internal transfers and duplicate payments. Transactions
+// that are part of a transfer are excluded from the duplicate search, so
+// moving money between accounts is never reported as a double charge.
+func Analyze(transactions []Transaction, opts Options) Result {
+	var result Result
+
+	// Collapse rows repeated by overlapping statement periods
+	if opts.DetectDoubleCharges {
+		transactions = deduplicateOverlaps(transactions)
+	} else {
+		transactions = deduplicateTransactions(transactions)
+	}
+
+	if opts.DetectTransfers {
+		pairs := findTransferPairs(transactions, opts.MaxTransferTimeDiff, opts.MaxTransferAmountDiff)
+
+		inTransfer := make(map[int]bool, 2*len(pairs))
+		for _, p := range pairs {
+			inTransfer[p.i] = true
+			inTransfer[p.j] = true
+			result.Transfers = append(result.Transfers, newTransferMatch(transactions[p.i], transactions[p.j]))
+		}
+
+		remaining := make([]Transaction, 0, len(transactions)-len(inTransfer))
+		for i, t := range transactions {
+			if !inTransfer[i] {
+				remaining = append(remaining, t)
+			}
+		}
+		transactions = remaining
+	}
+
+	result.Duplicates = findDuplicates(transactions, opts)
+	sortByConfidence(result.Duplicates)
+	result.Groups = GroupDuplicates(result.Duplicates, opts)
+
+	return result
+}
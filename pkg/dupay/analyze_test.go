@@ -0,0 +1,247 @@
+package dupay
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAnalyzeExcludesTransfersFromDuplicates(t *testing.T) {
+	baseTime := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	// Money moved from Optima to Mbank and spent on Mbank right away: the
+	// Optima debit and the Mbank purchase look like a duplicate payment
+	transactions := []Transaction{
+		{Bank: "Optima Bank", DateTime: baseTime, Amount: NewMoney(-500000, "KGS"), Description: "Transfer to Mbank"},
+		{Bank: "Mbank", DateTime: baseTime, Amount: NewMoney(500000, "KGS"), Description: "Transfer from Optima"},
+		{Bank: "Mbank", DateTime: baseTime.Add(30 * time.Second), Amount: NewMoney(-500000, "KGS"), Description: "Purchase"},
+	}
+
+	tests := []struct {
+		name               string
+		detectTransfers    bool
+		expectedTransfers  int
+		expectedDuplicates int
+	}{
+		{name: "transfers enabled", detectTransfers: true, expectedTransfers: 1, expectedDuplicates: 0},
+		{name: "transfers disabled", detectTransfers: false, expectedTransfers: 0, expectedDuplicates: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultOptions()
+			opts.DetectTransfers = tt.detectTransfers
+
+			result := Analyze(transactions, opts)
+			if len(result.Transfers) != tt.expectedTransfers {
+				t.Errorf("expected %d transfers, got %d", tt.expectedTransfers, len(result.Transfers))
+			}
+			if len(result.Duplicates) != tt.expectedDuplicates {
+				t.Errorf("expected %d duplicates, got %d", tt.expectedDuplicates, len(result.Duplicates))
+			}
+		})
+	}
+}
+
+func TestAnalyzeTransfersNeedEqualAmounts(t *testing.T) {
+	baseTime := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	// A payment charged twice, and an unrelated credit of a similar amount
+	// in a third account within the transfer window
+	transactions := []Transaction{
+		{Bank: "Optima Bank", DateTime: baseTime, Amount: NewMoney(-10000, "KGS"), Description: "GLOBUS"},
+		{Bank: "Mbank", DateTime: baseTime.Add(10 * time.Second), Amount: NewMoney(-10000, "KGS"), Description: "GLOBUS"},
+		{Bank: "Keremet", DateTime: baseTime.Add(time.Minute), Amount: NewMoney(10050, "KGS"), Description: "Refund"},
+	}
+
+	tests := []struct {
+		name               string
+		maxTransferDiff    int64
+		expectedTransfers  int
+		expectedDuplicates int
+	}{
+		{name: "exact amounts by default", maxTransferDiff: 0, expectedTransfers: 0, expectedDuplicates: 1},
+		{name: "tolerance allowed", maxTransferDiff: 100, expectedTransfers: 1, expectedDuplicates: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultOptions()
+			opts.MaxTransferAmountDiff = tt.maxTransferDiff
+
+			result := Analyze(transactions, opts)
+			if len(result.Transfers) != tt.expectedTransfers {
+				t.Errorf("expected %d transfers, got %d", tt.expectedTransfers, len(result.Transfers))
+			}
+			if len(result.Duplicates) != tt.expectedDuplicates {
+				t.Errorf("expected %d duplicates, got %d", tt.expectedDuplicates, len(result.Duplicates))
+			}
+		})
+	}
+}
+
+func TestAnalyzeMatchesFindDuplicatesWithoutTransfers(t *testing.T) {
+	transactions := generateTransactions(2000, 7)
+
+	opts := DefaultOptions()
+	opts.DetectTransfers = false
+
+	result := Analyze(transactions, opts)
+	expected := FindDuplicates(transactions, opts.MaxTimeDiff, opts.MaxAmountDiff)
+	if len(result.Duplicates) != len(expected) {
+		t.Errorf("expected %d duplicates, got %d", len(expected), len(result.Duplicates))
+	}
+}
+
+func TestAnalyzeDoubleCharges(t *testing.T) {
+	baseTime := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+	charge := func(file string, offset time.Duration) Transaction {
+		return Transaction{
+			Bank:     "Mbank",
+			DateTime: baseTime.Add(offset),
+			Amount:   NewMoney(-45000, "KGS"),
+			Source:   Source{File: file},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		transactions []Transaction
+		enabled      bool
+		expected     int
+	}{
+		{
+			name:         "double charge within one statement",
+			transactions: []Transaction{charge("dec.pdf", 0), charge("dec.pdf", 20*time.Second)},
+			enabled:      true,
+			expected:     1,
+		},
+		{
+			name:         "double charge ignored when disabled",
+			transactions: []Transaction{charge("dec.pdf", 0), charge("dec.pdf", 20*time.Second)},
+			enabled:      false,
+			expected:     0,
+		},
+		{
+			name:         "overlapping statements are not double charges",
+			transactions: []Transaction{charge("dec.pdf", 0), charge("q4.pdf", 0)},
+			enabled:      true,
+			expected:     0,
+		},
+		{
+			name:         "same bank in different files is not compared",
+			transactions: []Transaction{charge("dec.pdf", 0), charge("q4.pdf", 20*time.Second)},
+			enabled:      true,
+			expected:     0,
+		},
+		{
+			name:         "unknown source is never a double charge",
+			transactions: []Transaction{charge("", 0), charge("", 20*time.Second)},
+			enabled:      true,
+			expected:     0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultOptions()
+			opts.DetectDoubleCharges = tt.enabled
+
+			result := Analyze(tt.transactions, opts)
+			if len(result.Duplicates) != tt.expected {
+				t.Fatalf("expected %d duplicates, got %d", tt.expected, len(result.Duplicates))
+			}
+			for _, dup := range result.Duplicates {
+				if dup.Kind != DoubleCharge {
+					t.Errorf("expected kind %q, got %q", DoubleCharge, dup.Kind)
+				}
+			}
+		})
+	}
+}
+
+func TestAnalyzeConvertsCrossCurrencyPairs(t *testing.T) {
+	baseTime := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+	rates := NewExchangeRates("KGS")
+	rates.Add(baseTime, "USD", 87.45)
+
+	// The same purchase paid from a KGS card and a USD account, with the
+	// KGS side charged at a slightly worse rate than the official one
+	transactions := []Transaction{
+		{Bank: "Mbank", DateTime: baseTime, Amount: NewMoney(-132000, "KGS")},
+		{Bank: "Optima Bank", DateTime: baseTime.Add(20 * time.Second), Amount: NewMoney(-1500, "USD")},
+	}
+
+	tests := []struct {
+		name          string
+		rates         *ExchangeRates
+		maxPercent    float64
+		expectedCount int
+	}{
+		{name: "no rates", rates: nil, maxPercent: 2, expectedCount: 0},
+		{name: "within tolerance", rates: rates, maxPercent: 2, expectedCount: 1},
+		{name: "outside tolerance", rates: rates, maxPercent: 0.1, expectedCount: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultOptions()
+			opts.Rates = tt.rates
+			opts.MaxRateDiffPercent = tt.maxPercent
+
+			result := Analyze(transactions, opts)
+			if len(result.Duplicates) != tt.expectedCount {
+				t.Fatalf("expected %d duplicates, got %d", tt.expectedCount, len(result.Duplicates))
+			}
+			if tt.expectedCount == 0 {
+				return
+			}
+
+			dup := result.Duplicates[0]
+			expected := &Conversion{Currency: "KGS", Amount1: NewMoney(-132000, "KGS"), Amount2: NewMoney(-131175, "KGS")}
+			if !reflect.DeepEqual(dup.Conversion, expected) {
+				t.Errorf("expected conversion %+v, got %+v", expected, dup.Conversion)
+			}
+			if dup.AmountDiff != NewMoney(825, "KGS") {
+				t.Errorf("expected amount diff 8.25 KGS, got %v", dup.AmountDiff)
+			}
+		})
+	}
+}
+
+func TestAnalyzeCompareGross(t *testing.T) {
+	baseTime := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	transactions := []Transaction{
+		{Bank: "Optima Bank", DateTime: baseTime, Amount: NewMoney(-100000, "KGS"), Fee: NewMoney(2000, "KGS")},
+		{Bank: "Mbank", DateTime: baseTime, Amount: NewMoney(-100000, "KGS")},
+		{Bank: "Mbank", DateTime: baseTime.Add(time.Hour), Amount: NewMoney(-50000, "KGS")},
+		{Bank: "Optima Bank", DateTime: baseTime.Add(time.Hour), Amount: NewMoney(-49000, "KGS"), Fee: NewMoney(1000, "KGS")},
+	}
+
+	// A 1000.00 payment charged with a 20.00 fee only matches on net
+	// amounts; a 490.00 payment plus 10.00 fee matches 500.00 on gross amounts
+	tests := []struct {
+		name         string
+		compareGross bool
+		expected     time.Time
+	}{
+		{name: "net amounts", compareGross: false, expected: baseTime},
+		{name: "gross amounts", compareGross: true, expected: baseTime.Add(time.Hour)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultOptions()
+			opts.CompareGross = tt.compareGross
+
+			result := Analyze(transactions, opts)
+			if len(result.Duplicates) != 1 {
+				t.Fatalf("expected 1 duplicate, got %d", len(result.Duplicates))
+			}
+			if dup := result.Duplicates[0]; !dup.Transaction1.DateTime.Equal(tt.expected) || !dup.AmountDiff.IsZero() {
+				t.Errorf("expected an exact match at %v, got %v with diff %v", tt.expected, dup.Transaction1.DateTime, dup.AmountDiff)
+			}
+		})
+	}
+}
@@ -0,0 +1,242 @@
+package dupay
+
+import (
+	"math"
+	"sort"
+)
+
+// accountKey identifies the account a transaction was charged to, as
+// distinguished by sameAccount.
+func accountKey(t Transaction) string {
+	return t.Bank + "|" + t.Account
+}
+
+// assignMatches keeps a one-to-one subset of matches: between any two
+// accounts, every transaction keeps at most one counterpart. Of the matches
+// linked through shared transactions, as many as possible are kept, and
+// among those the set with the highest total confidence, found with the
+// Hungarian algorithm. Double charges within one account have no two sides
+// to assign between, so they are taken greedily from the most confident.
+//
+// pairs holds the transaction indexes of each match. The kept matches stay
+// in their original order.
+func assignMatches(pairs []indexPair, matches []DuplicateMatch) []DuplicateMatch {
+	costs := make([]float64, len(matches))
+	for k, m := range matches {
+		costs[k] = float64(maxConfidence - m.Confidence)
+	}
+
+	// Split the matches by the two accounts they link
+	between := make(map[string][]int)
+	var doubleCharges []int
+	for k, m := range matches {
+		if m.Kind == DoubleCharge {
+			doubleCharges = append(doubleCharges, k)
+			continue
+		}
+		a, b := accountKey(m.Transaction1), accountKey(m.Transaction2)
+		key := min(a, b) + "\x00" + max(a, b)
+		between[key] = append(between[key], k)
+	}
+
+	keep := make([]bool, len(matches))
+	for _, edges := range between {
+		for _, component := range connectedMatches(edges, pairs) {
+			assignComponent(component, pairs, matches, costs, keep)
+		}
+	}
+
+	sort.SliceStable(doubleCharges, func(a, b int) bool {
+		return costs[doubleCharges[a]] < costs[doubleCharges[b]]
+	})
+	used := make(map[int]bool)
+	for _, k := range doubleCharges {
+		p := pairs[k]
+		if used[p.i] || used[p.j] {
+			continue
+		}
+		used[p.i], used[p.j] = true, true
+		keep[k] = true
+	}
+
+	var result []DuplicateMatch
+	for k, m := range matches {
+		if keep[k] {
+			result = append(result, m)
+		}
+	}
+	return result
+}
+
+// connectedMatches splits the matches at indexes edges into sets linked
+// through shared transactions, so each set can be assigned on its own.
+func connectedMatches(edges []int, pairs []indexPair) [][]int {
+	parent := make(map[int]int)
+	var find func(int) int
+	find = func(x int) int {
+		p, ok := parent[x]
+		if !ok {
+			parent[x] = x
+			return x
+		}
+		if p != x {
+			p = find(p)
+			parent[x] = p
+		}
+		return p
+	}
+	for _, k := range edges {
+		a, b := find(pairs[k].i), find(pairs[k].j)
+		if a != b {
+			parent[max(a, b)] = min(a, b)
+		}
+	}
+
+	var components [][]int
+	position := make(map[int]int)
+	for _, k := range edges {
+		root := find(pairs[k].i)
+		pos, ok := position[root]
+		if !ok {
+			pos = len(components)
+			position[root] = pos
+			components = append(components, nil)
+		}
+		components[pos] = append(components[pos], k)
+	}
+	return components
+}
+
+// assignComponent marks in keep the best one-to-one subset of the matches at
+// indexes component, which all link the same two accounts.
+func assignComponent(component []int, pairs []indexPair, matches []DuplicateMatch, costs []float64, keep []bool) {
+	if len(component) == 1 {
+		keep[component[0]] = true
+		return
+	}
+
+	// One side per account; the side of a transaction is fixed by its account
+	leftAccount := accountKey(matches[component[0]].Transaction1)
+	var left, right []int
+	leftPos, rightPos := make(map[int]int), make(map[int]int)
+	add := func(side *[]int, pos map[int]int, x int) int {
+		if p, ok := pos[x]; ok {
+			return p
+		}
+		pos[x] = len(*side)
+		*side = append(*side, x)
+		return pos[x]
+	}
+	type cell struct{ row, col int }
+	edge := make(map[cell]int)
+	for _, k := range component {
+		l, r := pairs[k].i, pairs[k].j
+		if accountKey(matches[k].Transaction1) != leftAccount {
+			l, r = r, l
+		}
+		edge[cell{add(&left, leftPos, l), add(&right, rightPos, r)}] = k
+	}
+
+	// The assignment needs no more rows than columns
+	transposed := len(left) > len(right)
+	rows, cols := len(left), len(right)
+	if transposed {
+		rows, cols = cols, rows
+	}
+
+	// A missing match costs more than any set of real ones, so the assignment
+	// keeps as many real matches as it can
+	missing := float64(maxConfidence * (rows + 1))
+	cost := make([][]float64, rows)
+	for r := range cost {
+		cost[r] = make([]float64, cols)
+		for c := range cost[r] {
+			cost[r][c] = missing
+			key := cell{r, c}
+			if transposed {
+				key = cell{c, r}
+			}
+			if k, ok := edge[key]; ok {
+				cost[r][c] = costs[k]
+			}
+		}
+	}
+
+	for r, c := range hungarian(cost) {
+		key := cell{r, c}
+		if transposed {
+			key = cell{c, r}
+		}
+		if k, ok := edge[key]; ok {
+			keep[k] = true
+		}
+	}
+}
+
+// hungarian solves the assignment problem for a cost matrix with at least
+// one row and no more rows than columns. It returns the column assigned to
+// each row, at the lowest total cost.
+func hungarian(cost [][]float64) []int {
+	n, m := len(cost), len(cost[0])
+
+	// Potentials of rows and columns, and the row assigned to each column,
+	// all 1-based with column 0 as the unassigned sentinel
+	u := make([]float64, n+1)
+	v := make([]float64, m+1)
+	assigned := make([]int, m+1)
+	way := make([]int, m+1)
+
+	for i := 1; i <= n; i++ {
+		assigned[0] = i
+		j0 := 0
+		minv := make([]float64, m+1)
+		for j := range minv {
+			minv[j] = math.Inf(1)
+		}
+		used := make([]bool, m+1)
+
+		// Grow a shortest augmenting path from row i to a free column
+		for {
+			used[j0] = true
+			i0, delta, j1 := assigned[j0], math.Inf(1), 0
+			for j := 1; j <= m; j++ {
+				if used[j] {
+					continue
+				}
+				if cur := cost[i0-1][j-1] - u[i0] - v[j]; cur < minv[j] {
+					minv[j], way[j] = cur, j0
+				}
+				if minv[j] < delta {
+					delta, j1 = minv[j], j
+				}
+			}
+			for j := 0; j <= m; j++ {
+				if used[j] {
+					u[assigned[j]] += delta
+					v[j] -= delta
+				} else {
+					minv[j] -= delta
+				}
+			}
+			j0 = j1
+			if assigned[j0] == 0 {
+				break
+			}
+		}
+
+		// Flip the assignments along the path
+		for j0 != 0 {
+			j1 := way[j0]
+			assigned[j0] = assigned[j1]
+			j0 = j1
+		}
+	}
+
+	result := make([]int, n)
+	for j := 1; j <= m; j++ {
+		if assigned[j] != 0 {
+			result[assigned[j]-1] = j - 1
+		}
+	}
+	return result
+}
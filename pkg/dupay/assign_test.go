@@ -0,0 +1,149 @@
+package dupay
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+	"time"
+)
+
+func TestFindDuplicatesOneToOne(t *testing.T) {
+	baseTime := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+	debit := func(bank string, offset time.Duration, minor int64, description string) Transaction {
+		return Transaction{Bank: bank, DateTime: baseTime.Add(offset), Amount: NewMoney(minor, "KGS"), Description: description}
+	}
+
+	tests := []struct {
+		name         string
+		transactions []Transaction
+		// expected lists the kept pairs by description
+		expected [][2]string
+	}{
+		{
+			name: "closest of two candidates",
+			transactions: []Transaction{
+				debit("Mbank", 0, -10000, "Payment GLOBUS"),
+				debit("Optima Bank", 10*time.Second, -10000, "NAMBA FOOD"),
+				debit("Optima Bank", 20*time.Second, -10000, "GLOBUS"),
+			},
+			expected: [][2]string{{"Payment GLOBUS", "GLOBUS"}},
+		},
+		{
+			name: "closer amount wins without descriptions",
+			transactions: []Transaction{
+				debit("Mbank", 0, -10000, ""),
+				debit("Optima Bank", 0, -10090, ""),
+				debit("Optima Bank", 0, -10010, "card"),
+			},
+			expected: [][2]string{{"", "card"}},
+		},
+		{
+			// Taking the closest pair first would leave the second Mbank
+			// payment without a counterpart
+			name: "keeps as many pairs as possible",
+			transactions: []Transaction{
+				debit("Mbank", 0, -10000, "first"),
+				debit("Optima Bank", 10*time.Second, -10000, "first"),
+				debit("Optima Bank", -30*time.Second, -10000, "other"),
+				debit("Mbank", time.Minute, -10000, "second"),
+			},
+			expected: [][2]string{{"first", "other"}, {"first", "second"}},
+		},
+		{
+			name: "one counterpart in each other account",
+			transactions: []Transaction{
+				debit("Mbank", 0, -10000, "SHOP"),
+				debit("Optima Bank", 0, -10000, "SHOP"),
+				debit("Demir", 0, -10000, "SHOP"),
+			},
+			expected: [][2]string{{"SHOP", "SHOP"}, {"SHOP", "SHOP"}, {"SHOP", "SHOP"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := Options{MaxTimeDiff: time.Minute, MaxAmountDiff: 100, OneToOne: true}
+			matches := findDuplicates(tt.transactions, opts)
+
+			var got [][2]string
+			for _, m := range matches {
+				got = append(got, [2]string{m.Transaction1.Description, m.Transaction2.Description})
+			}
+			if len(got) != len(tt.expected) {
+				t.Fatalf("expected pairs %q, got %q", tt.expected, got)
+			}
+			for i := range got {
+				if got[i] != tt.expected[i] {
+					t.Errorf("expected pairs %q, got %q", tt.expected, got)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestFindDuplicatesOneToOneDoubleCharges(t *testing.T) {
+	baseTime := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+	charge := func(offset time.Duration, line int) Transaction {
+		return Transaction{Bank: "Mbank", DateTime: baseTime.Add(offset), Amount: NewMoney(-10000, "KGS"), Source: Source{File: "mbank.pdf", StartLine: line}}
+	}
+	// Three charges within a minute of each other form three pairs, of which
+	// only one can be kept
+	transactions := []Transaction{charge(0, 1), charge(20*time.Second, 2), charge(50*time.Second, 3)}
+
+	opts := Options{MaxTimeDiff: time.Minute, MaxAmountDiff: 100, DetectDoubleCharges: true, OneToOne: true}
+	matches := findDuplicates(transactions, opts)
+	if len(matches) != 1 {
+		t.Fatalf("expected 1 double charge, got %d", len(matches))
+	}
+	if m := matches[0]; m.Transaction1.Source.StartLine != 1 || m.Transaction2.Source.StartLine != 2 {
+		t.Errorf("expected the closest charges, lines 1 and 2, got lines %d and %d", m.Transaction1.Source.StartLine, m.Transaction2.Source.StartLine)
+	}
+}
+
+// assignmentCost returns the lowest total cost of assigning every row of cost
+// to a distinct column by trying every assignment.
+func assignmentCost(cost [][]float64, row int, used []bool) float64 {
+	if row == len(cost) {
+		return 0
+	}
+	best := math.Inf(1)
+	for c := range cost[row] {
+		if used[c] {
+			continue
+		}
+		used[c] = true
+		best = min(best, cost[row][c]+assignmentCost(cost, row+1, used))
+		used[c] = false
+	}
+	return best
+}
+
+func TestHungarianMatchesBruteForce(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	for range 200 {
+		rows := 1 + r.IntN(5)
+		cols := rows + r.IntN(3)
+		cost := make([][]float64, rows)
+		for i := range cost {
+			cost[i] = make([]float64, cols)
+			for j := range cost[i] {
+				cost[i][j] = float64(r.IntN(10))
+			}
+		}
+
+		assignment := hungarian(cost)
+		seen := make(map[int]bool)
+		total := 0.0
+		for i, c := range assignment {
+			if seen[c] {
+				t.Fatalf("column %d assigned twice in %v", c, cost)
+			}
+			seen[c] = true
+			total += cost[i][c]
+		}
+		if expected := assignmentCost(cost, 0, make([]bool, cols)); total != expected {
+			t.Fatalf("expected total cost %v, got %v for %v", expected, total, cost)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package dupay
+
+import (
+	"math"
+	"slices"
+)
+
+// maxConfidence is the confidence of two identical debits.
+const maxConfidence = 100
+
+// Weights of the parts of a confidence score; they add up to maxConfidence.
+const (
+	timeWeight     = 35
+	amountWeight   = 35
+	merchantWeight = 30
+)
+
+// transferPenalty is the percentage taken off the confidence of a pair whose
+// debits could also be transfers to another of the user's accounts: moving
+// money to a second account and paying the same amount from there looks like
+// a duplicate but isn't one.
+const transferPenalty = 50
+
+// confidence scores how likely a match is the same payment charged twice,
+// from 0 to maxConfidence. Time and amount count for more the further they
+// are within their tolerances, and merchants for how similar they are.
+// transferLike marks pairs that also match a transfer.
+func confidence(m DuplicateMatch, transferLike bool, opts Options) int {
+	amount := fraction(float64(m.AmountDiff.Minor), float64(opts.MaxAmountDiff))
+	if c := m.Conversion; c != nil {
+		larger := max(absMinor(c.Amount1.Minor), absMinor(c.Amount2.Minor))
+		amount = fraction(float64(m.AmountDiff.Minor), float64(larger)*opts.MaxRateDiffPercent/100)
+	}
+
+	score := timeWeight*(1-fraction(float64(m.TimeDiff), float64(opts.MaxTimeDiff))) +
+		amountWeight*(1-amount) +
+		merchantWeight*merchantSimilarity(m.Transaction1, m.Transaction2)
+	if transferLike {
+		score = score * (100 - transferPenalty) / 100
+	}
+	return int(math.Round(score))
+}
+
+// fraction returns v as a fraction of limit, capped at 1. A zero limit only
+// allows zero, so any value within it is a perfect fit.
+func fraction(v, limit float64) float64 {
+	if limit <= 0 {
+		return 0
+	}
+	return min(v/limit, 1)
+}
+
+// transferDebits returns the debits that could be one side of a transfer: a
+// credit of the same amount in another account within MaxTransferTimeDiff and
+// MaxTransferAmountDiff.
+// It returns none unless DetectTransfers is set.
+func transferDebits(transactions []Transaction, opts Options) map[int]bool {
+	debits := make(map[int]bool)
+	if !opts.DetectTransfers {
+		return debits
+	}
+	for _, c := range findTransferCandidates(transactions, opts.MaxTransferTimeDiff, opts.MaxTransferAmountDiff) {
+		debits[c.debit] = true
+	}
+	return debits
+}
+
+// sortByConfidence orders matches from the most to the least confident,
+// keeping the order of matches with the same confidence.
+func sortByConfidence(matches []DuplicateMatch) {
+	slices.SortStableFunc(matches, func(a, b DuplicateMatch) int {
+		return b.Confidence - a.Confidence
+	})
+}
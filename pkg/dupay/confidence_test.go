@@ -0,0 +1,107 @@
+package dupay
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConfidence(t *testing.T) {
+	baseTime := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+	match := func(timeDiff time.Duration, amountDiff int64, description1, description2 string) DuplicateMatch {
+		return DuplicateMatch{
+			Transaction1: Transaction{Bank: "Optima Bank", DateTime: baseTime, Amount: NewMoney(-10000, "KGS"), Description: description1},
+			Transaction2: Transaction{Bank: "Mbank", DateTime: baseTime.Add(timeDiff), Amount: NewMoney(-10000-amountDiff, "KGS"), Description: description2},
+			TimeDiff:     timeDiff,
+			AmountDiff:   NewMoney(amountDiff, "KGS"),
+		}
+	}
+	opts := Options{MaxTimeDiff: time.Minute, MaxAmountDiff: 100, MaxRateDiffPercent: 2}
+
+	tests := []struct {
+		name         string
+		match        DuplicateMatch
+		transferLike bool
+		opts         Options
+		expected     int
+	}{
+		{"identical", match(0, 0, "GLOBUS", "globus"), false, opts, 100},
+		{"at both tolerances with nothing in common", match(time.Minute, 100, "GLOBUS", "NAMBA"), false, opts, 0},
+		{"half the time tolerance", match(30*time.Second, 0, "GLOBUS", "GLOBUS"), false, opts, 83},
+		{"same merchant in other words", match(0, 0, "Payment to Globus", "ГЛОБУС Бишкек"), false, opts, 100},
+		{"similar merchants", match(0, 0, "GLOBUS", "GLOBUS 12"), false, opts, 95},
+		{"no descriptions", match(0, 0, "", ""), false, opts, 70},
+		{"also a transfer", match(0, 0, "GLOBUS", "GLOBUS"), true, opts, 50},
+		{"zero tolerances", match(0, 0, "", ""), false, Options{}, 70},
+		{
+			name: "converted amounts within the rate tolerance",
+			match: DuplicateMatch{
+				TimeDiff:   0,
+				AmountDiff: NewMoney(1000, "KGS"),
+				Conversion: &Conversion{Currency: "KGS", Amount1: NewMoney(-100000, "KGS"), Amount2: NewMoney(-101000, "KGS")},
+			},
+			opts:     opts,
+			expected: 53,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := confidence(tt.match, tt.transferLike, tt.opts); got != tt.expected {
+				t.Errorf("expected confidence %d, got %d", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestFindDuplicatesTransferPenalty(t *testing.T) {
+	baseTime := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+	// The Mbank credit could be the other side of either debit
+	transactions := []Transaction{
+		{Bank: "Optima Bank", DateTime: baseTime, Amount: NewMoney(-50000, "KGS"), Description: "Transfer"},
+		{Bank: "Mbank", DateTime: baseTime.Add(5 * time.Second), Amount: NewMoney(50000, "KGS"), Description: "Transfer"},
+		{Bank: "Demir", DateTime: baseTime.Add(10 * time.Second), Amount: NewMoney(-50000, "KGS"), Description: "Transfer"},
+	}
+
+	for _, detect := range []bool{false, true} {
+		opts := Options{MaxTimeDiff: time.Minute, MaxAmountDiff: 100, DetectTransfers: detect, MaxTransferTimeDiff: time.Minute}
+		matches := findDuplicates(transactions, opts)
+		if len(matches) != 1 {
+			t.Fatalf("expected 1 match, got %d", len(matches))
+		}
+		expected := 94
+		if detect {
+			expected = 47
+		}
+		if matches[0].Confidence != expected {
+			t.Errorf("with transfer detection %v: expected confidence %d, got %d", detect, expected, matches[0].Confidence)
+		}
+	}
+}
+
+func TestAnalyzeSortsByConfidence(t *testing.T) {
+	baseTime := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+	transactions := []Transaction{
+		{Bank: "Optima Bank", DateTime: baseTime, Amount: NewMoney(-10000, "KGS"), Description: "NAMBA"},
+		{Bank: "Mbank", DateTime: baseTime.Add(50 * time.Second), Amount: NewMoney(-10080, "KGS"), Description: "Taxi"},
+		{Bank: "Optima Bank", DateTime: baseTime.Add(time.Hour), Amount: NewMoney(-20000, "KGS"), Description: "GLOBUS"},
+		{Bank: "Mbank", DateTime: baseTime.Add(time.Hour), Amount: NewMoney(-20000, "KGS"), Description: "GLOBUS"},
+	}
+
+	opts := DefaultOptions()
+	result := Analyze(transactions, opts)
+	if len(result.Duplicates) != 2 {
+		t.Fatalf("expected 2 duplicates, got %d", len(result.Duplicates))
+	}
+	if result.Duplicates[0].Transaction1.Description != "GLOBUS" || result.Duplicates[0].Confidence != 100 {
+		t.Errorf("expected the identical GLOBUS pair first, got %+v", result.Duplicates[0])
+	}
+	if result.Groups[0].Confidence != 100 || result.Groups[1].Confidence >= 50 {
+		t.Errorf("expected groups ordered by confidence, got %d and %d", result.Groups[0].Confidence, result.Groups[1].Confidence)
+	}
+
+	opts.MinConfidence = 50
+	result = Analyze(transactions, opts)
+	if len(result.Duplicates) != 1 || result.Duplicates[0].Confidence != 100 {
+		t.Errorf("expected only the confident pair above the cutoff, got %+v", result.Duplicates)
+	}
+}
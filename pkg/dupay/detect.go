@@ -0,0 +1,88 @@
+package dupay
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+// MaxScore is the highest detection score a parser can return.
+const MaxScore = 100
+
+// ErrAmbiguousParser is reported for a file when several parsers give it the
+// same highest score.
+var ErrAmbiguousParser = errors.New("several parsers match this PDF equally well")
+
+// Marker is a text fragment that identifies a bank's statements. Weight is
+// how much finding it adds to the parser's detection score.
+type Marker struct {
+	Text   string
+	Weight int
+}
+
+// Candidate is a parser together with the score it gave to some content.
+type Candidate struct {
+	Parser BankParser
+	Score  int
+}
+
+// Detection is the outcome of scoring some content with every parser.
+type Detection struct {
+	// Candidates lists the parsers with a positive score, best first.
+	Candidates []Candidate
+}
+
+// DetectParser scores the content with every parser. Parsers with equal
+// scores keep their registration order.
+func DetectParser(parsers []BankParser, content string) Detection {
+	var d Detection
+	for _, p := range parsers {
+		if score := p.Score(content); score > 0 {
+			d.Candidates = append(d.Candidates, Candidate{Parser: p, Score: score})
+		}
+	}
+	sort.SliceStable(d.Candidates, func(i, j int) bool {
+		return d.Candidates[i].Score > d.Candidates[j].Score
+	})
+	return d
+}
+
+// Best returns the highest-scoring candidate. It returns ErrNoParser when no
+// parser recognized the content and ErrAmbiguousParser when the top score is
+// shared by several parsers.
+func (d Detection) Best() (Candidate, error) {
+	if len(d.Candidates) == 0 {
+		return Candidate{}, ErrNoParser
+	}
+	if d.Ambiguous() {
+		return Candidate{}, ErrAmbiguousParser
+	}
+	return d.Candidates[0], nil
+}
+
+// Ambiguous reports whether more than one parser shares the top score.
+func (d Detection) Ambiguous() bool {
+	return len(d.Candidates) > 1 && d.Candidates[0].Score == d.Candidates[1].Score
+}
+
+// ParserByID returns the parser with the given ID, ignoring case, or nil.
+func ParserByID(parsers []BankParser, id string) BankParser {
+	for _, p := range parsers {
+		if strings.EqualFold(p.ID(), id) {
+			return p
+		}
+	}
+	return nil
+}
+
+// scoreMarkers adds up the weights of the markers found in content, capped
+// at MaxScore.
+func scoreMarkers(content string, markers []Marker) int {
+	score := 0
+	for _, m := range markers {
+		if strings.Contains(content, m.Text) {
+			score += m.Weight
+		}
+	}
+	return min(score, MaxScore)
+}
@@ -0,0 +1,123 @@
+package dupay
+
+import (
+	"errors"
+	"testing"
+)
+
+// stubParser is a BankParser with a fixed score, for detection tests.
+type stubParser struct {
+	id    string
+	score int
+}
+
+func (p *stubParser) Parse(content string) (ParseResult, error) { return ParseResult{}, nil }
+func (p *stubParser) BankName() string                          { return "Bank " + p.id }
+func (p *stubParser) ID() string                                { return p.id }
+func (p *stubParser) Score(content string) int                  { return p.score }
+func (p *stubParser) Markers() []Marker                         { return nil }
+
+func TestDetectParser(t *testing.T) {
+	tests := []struct {
+		name        string
+		parsers     []BankParser
+		expectedID  string
+		expectedErr error
+		candidates  int
+	}{
+		{
+			name:        "no parsers match",
+			parsers:     []BankParser{&stubParser{id: "a"}, &stubParser{id: "b"}},
+			expectedErr: ErrNoParser,
+		},
+		{
+			name:       "single match",
+			parsers:    []BankParser{&stubParser{id: "a"}, &stubParser{id: "b", score: 40}},
+			expectedID: "b",
+			candidates: 1,
+		},
+		{
+			name:       "highest score wins regardless of order",
+			parsers:    []BankParser{&stubParser{id: "a", score: 20}, &stubParser{id: "b", score: 80}},
+			expectedID: "b",
+			candidates: 2,
+		},
+		{
+			name:        "tie is ambiguous",
+			parsers:     []BankParser{&stubParser{id: "a", score: 60}, &stubParser{id: "b", score: 60}},
+			expectedErr: ErrAmbiguousParser,
+			candidates:  2,
+		},
+		{
+			name: "tie below the best is not ambiguous",
+			parsers: []BankParser{
+				&stubParser{id: "a", score: 20},
+				&stubParser{id: "b", score: 20},
+				&stubParser{id: "c", score: 90},
+			},
+			expectedID: "c",
+			candidates: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detection := DetectParser(tt.parsers, "content")
+			if len(detection.Candidates) != tt.candidates {
+				t.Errorf("expected %d candidates, got %d", tt.candidates, len(detection.Candidates))
+			}
+
+			best, err := detection.Best()
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("expected error %v, got %v", tt.expectedErr, err)
+			}
+			if err == nil && best.Parser.ID() != tt.expectedID {
+				t.Errorf("expected parser %q, got %q", tt.expectedID, best.Parser.ID())
+			}
+		})
+	}
+}
+
+func TestDetectParserOptimaMentioningMbank(t *testing.T) {
+	// An Optima statement listing a transfer to Mbank must not be read as Mbank
+	content := `Optima Bank
+optimabank.kg
+15.01.2025
+10:30
+Transfer to Mbank card
+-1 500.00
+KGS`
+
+	best, err := DetectParser(DefaultParsers(), content).Best()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if best.Parser.ID() != "optima" {
+		t.Errorf("expected optima parser, got %q", best.Parser.ID())
+	}
+}
+
+func TestParserByID(t *testing.T) {
+	parsers := DefaultParsers()
+
+	if p := ParserByID(parsers, "MBANK"); p == nil || p.BankName() != "Mbank" {
+		t.Errorf("expected Mbank parser for case-insensitive ID, got %v", p)
+	}
+	if p := ParserByID(parsers, "unknown"); p != nil {
+		t.Errorf("expected no parser, got %q", p.BankName())
+	}
+}
+
+func TestScoreMarkers(t *testing.T) {
+	markers := []Marker{{Text: "alpha", Weight: 60}, {Text: "beta", Weight: 60}, {Text: "gamma", Weight: 10}}
+
+	if score := scoreMarkers("alpha gamma", markers); score != 70 {
+		t.Errorf("expected 70, got %d", score)
+	}
+	if score := scoreMarkers("alpha beta gamma", markers); score != MaxScore {
+		t.Errorf("expected score capped at %d, got %d", MaxScore, score)
+	}
+	if score := scoreMarkers("nothing", markers); score != 0 {
+		t.Errorf("expected 0, got %d", score)
+	}
+}
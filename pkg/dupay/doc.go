@@ -0,0 +1,19 @@
+// Package dupay detects duplicate payments across bank statement PDFs.
+//
+// It provides the Transaction model, bank-specific statement parsers, PDF
+// text and table layout extraction and the duplicate finder used by the
+// dupay command, so other tools can embed the same logic:
+//
+//	doc, err := dupay.ExtractPDF("mbank.pdf")
+//	if err != nil {
+//		return err
+//	}
+//	parsed, err := doc.Parse("mbank.pdf", dupay.NewMbankParser())
+//	if err != nil {
+//		return err
+//	}
+//	for _, w := range parsed.Warnings {
+//		log.Println(w)
+//	}
+//	result := dupay.Analyze(parsed.Transactions, dupay.DefaultOptions())
+package dupay
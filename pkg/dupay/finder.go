@@ -0,0 +1,318 @@
+package dupay
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+)
+
+// deduplicateTransactions removes duplicate transactions from the same account
+// (same account, same datetime, same amount - likely from overlapping statement periods)
+func deduplicateTransactions(transactions []Transaction) []Transaction {
+	seen := make(map[string]bool)
+	var result []Transaction
+
+	for _, t := range transactions {
+		// Create a unique key for each transaction
+		key := dedupKey(t)
+		if !seen[key] {
+			seen[key] = true
+			result = append(result, t)
+		}
+	}
+
+	return result
+}
+
+// dedupKey identifies rows that are the same charge: same account, same
+// minute and same amount.
+func dedupKey(t Transaction) string {
+	return fmt.Sprintf("%s|%s|%s|%d|%s", t.Bank, t.Account, t.DateTime.Format("2006-01-02 15:04"), t.Amount.Minor, t.Amount.Currency)
+}
+
+// deduplicateOverlaps removes rows repeated because statement files overlap,
+// while keeping rows that are repeated within a single file. A row that
+// appears n times in one file and m times in another is kept max(n, m) times,
+// so a genuine double charge survives even when both statements contain it.
+func deduplicateOverlaps(transactions []Transaction) []Transaction {
+	kept := make(map[string]int)
+	seenInFile := make(map[string]int)
+	var result []Transaction
+
+	for _, t := range transactions {
+		key := dedupKey(t)
+		fileKey := key + "|" + t.Source.File
+
+		seenInFile[fileKey]++
+		if seenInFile[fileKey] > kept[key] {
+			kept[key]++
+			result = append(result, t)
+		}
+	}
+
+	return result
+}
+
+// sameStatement reports whether both transactions come from the same known file.
+func sameStatement(t1, t2 Transaction) bool {
+	return t1.Source.File != "" && t1.Source.File == t2.Source.File
+}
+
+// FindDuplicates finds potential duplicate transactions across different accounts
+// Parameters:
+//   - transactions: all transactions from all banks
+//   - maxTimeDiff: maximum time difference to consider (e.g., 1 minute)
+//   - maxAmountDiff: maximum amount difference in minor units of the pair's currency (e.g., 100 for 1.00)
+//
+// Debits are bucketed by currency and sorted by DateTime, so each transaction
+// is only compared with the transactions that follow it within maxTimeDiff.
+// Matches are returned in the same order as a full pairwise comparison would
+// produce them.
+func FindDuplicates(transactions []Transaction, maxTimeDiff time.Duration, maxAmountDiff int64) []DuplicateMatch {
+	// First, deduplicate transactions from overlapping statement periods
+	transactions = deduplicateTransactions(transactions)
+
+	return findDuplicates(transactions, Options{MaxTimeDiff: maxTimeDiff, MaxAmountDiff: maxAmountDiff})
+}
+
+// findDuplicates runs the sort-and-sweep search on already deduplicated
+// transactions, using the tolerances, double-charge and exchange-rate
+// settings of opts. With DetectDoubleCharges set, pairs from the same bank
+// are reported as DoubleCharge when both come from the same statement file.
+// Pairs below MinConfidence, or of different merchants with SameMerchant
+// set, are dropped, and with OneToOne set, only the best one-to-one set of
+// the rest is kept.
+func findDuplicates(transactions []Transaction, opts Options) []DuplicateMatch {
+	maxTimeDiff, maxAmountDiff, doubleCharges, gross := opts.MaxTimeDiff, opts.MaxAmountDiff, opts.DetectDoubleCharges, opts.CompareGross
+
+	// Both should be debits (negative amounts) for duplicate payment detection,
+	// and only transactions sharing a currency are compared. Foreign-currency
+	// transactions go into the buckets of both their currencies.
+	buckets := make(map[string][]int)
+	for i, t := range transactions {
+		if !t.Amount.IsNegative() {
+			continue
+		}
+		buckets[t.Amount.Currency] = append(buckets[t.Amount.Currency], i)
+		if c := t.OriginalAmount.Currency; c != "" && c != t.Amount.Currency {
+			buckets[c] = append(buckets[c], i)
+		}
+	}
+
+	var pairs []indexPair
+	for currency, bucket := range buckets {
+		sort.SliceStable(bucket, func(a, b int) bool {
+			return transactions[bucket[a]].DateTime.Before(transactions[bucket[b]].DateTime)
+		})
+
+		for a := 0; a < len(bucket); a++ {
+			t1 := transactions[bucket[a]]
+			for b := a + 1; b < len(bucket); b++ {
+				t2 := transactions[bucket[b]]
+
+				// Everything after this point is even further away in time
+				if t2.DateTime.Sub(t1.DateTime) > maxTimeDiff {
+					break
+				}
+
+				// Skip if same account, unless looking for double charges within a statement
+				if sameAccount(t1, t2) && !(doubleCharges && sameStatement(t1, t2)) {
+					continue
+				}
+
+				// A pair sharing several currencies is only considered in the
+				// bucket of the currency it is compared in
+				if c, ok := commonCurrency(t1, t2); !ok || c != currency {
+					continue
+				}
+
+				// Check amount difference (compare absolute values since both are negative)
+				if amountDiff(t1.amountIn(currency, gross), t2.amountIn(currency, gross)).Minor > maxAmountDiff {
+					continue
+				}
+
+				pairs = append(pairs, newIndexPair(bucket[a], bucket[b]))
+			}
+		}
+	}
+
+	// Debits sharing no currency can only be compared through exchange rates
+	var conversions map[indexPair]*Conversion
+	if opts.Rates != nil {
+		conversions = findConvertedPairs(transactions, opts)
+		for p := range conversions {
+			pairs = append(pairs, p)
+		}
+	}
+
+	// Restore the input order so results don't depend on map iteration or sorting
+	sort.Slice(pairs, func(a, b int) bool {
+		if pairs[a].i != pairs[b].i {
+			return pairs[a].i < pairs[b].i
+		}
+		return pairs[a].j < pairs[b].j
+	})
+
+	transferLike := transferDebits(transactions, opts)
+
+	var matches []DuplicateMatch
+	var kept []indexPair
+	for _, p := range pairs {
+		t1 := transactions[p.i]
+		t2 := transactions[p.j]
+
+		if opts.SameMerchant && merchantSimilarity(t1, t2) < sameMerchantSimilarity {
+			continue
+		}
+
+		kind := CrossBank
+		if sameAccount(t1, t2) {
+			kind = DoubleCharge
+		}
+
+		match := DuplicateMatch{
+			Kind:         kind,
+			Transaction1: t1,
+			Transaction2: t2,
+			TimeDiff:     absDuration(t1.DateTime.Sub(t2.DateTime)),
+		}
+		if c, ok := conversions[p]; ok {
+			match.Conversion = c
+			match.AmountDiff = amountDiff(c.Amount1, c.Amount2)
+		} else {
+			currency, _ := commonCurrency(t1, t2)
+			match.AmountDiff = amountDiff(t1.amountIn(currency, gross), t2.amountIn(currency, gross))
+		}
+		match.Confidence = confidence(match, transferLike[p.i] || transferLike[p.j], opts)
+		if match.Confidence < opts.MinConfidence {
+			continue
+		}
+		matches = append(matches, match)
+		kept = append(kept, p)
+	}
+
+	if opts.OneToOne {
+		matches = assignMatches(kept, matches)
+	}
+
+	return matches
+}
+
+// findConvertedPairs sweeps all debits by time and compares the pairs that
+// share no currency after converting both into the base currency of
+// opts.Rates, each at the rate of its own date. Pairs whose converted amounts
+// differ by at most opts.MaxRateDiffPercent of the larger one are returned
+// with their conversion.
+func findConvertedPairs(transactions []Transaction, opts Options) map[indexPair]*Conversion {
+	var debits []int
+	for i, t := range transactions {
+		if t.Amount.IsNegative() {
+			debits = append(debits, i)
+		}
+	}
+	sort.SliceStable(debits, func(a, b int) bool {
+		return transactions[debits[a]].DateTime.Before(transactions[debits[b]].DateTime)
+	})
+
+	base := opts.Rates.Base
+	conversions := make(map[indexPair]*Conversion)
+	for a := 0; a < len(debits); a++ {
+		t1 := transactions[debits[a]]
+		for b := a + 1; b < len(debits); b++ {
+			t2 := transactions[debits[b]]
+
+			if t2.DateTime.Sub(t1.DateTime) > opts.MaxTimeDiff {
+				break
+			}
+			if sameAccount(t1, t2) && !(opts.DetectDoubleCharges && sameStatement(t1, t2)) {
+				continue
+			}
+			if _, ok := commonCurrency(t1, t2); ok {
+				continue
+			}
+
+			c1, ok1 := opts.Rates.Convert(t1.amountIn(t1.Amount.Currency, opts.CompareGross), base, t1.DateTime)
+			c2, ok2 := opts.Rates.Convert(t2.amountIn(t2.Amount.Currency, opts.CompareGross), base, t2.DateTime)
+			if !ok1 || !ok2 {
+				continue
+			}
+
+			larger := max(absMinor(c1.Minor), absMinor(c2.Minor))
+			if float64(amountDiff(c1, c2).Minor) > float64(larger)*opts.MaxRateDiffPercent/100 {
+				continue
+			}
+
+			// Keep Amount1 and Amount2 aligned with the match's transaction order
+			p := newIndexPair(debits[a], debits[b])
+			if p.i != debits[a] {
+				c1, c2 = c2, c1
+			}
+			conversions[p] = &Conversion{Currency: base, Amount1: c1, Amount2: c2}
+		}
+	}
+	return conversions
+}
+
+// commonCurrency picks the currency two transactions are compared in. The
+// original payment currency is preferred, since conversion rates differ
+// between banks: two USD purchases are compared in USD even when both
+// accounts are in KGS. Otherwise the account currencies are compared, and
+// finally one side's original amount against the other's account amount.
+// It reports false when the transactions share no currency.
+func commonCurrency(a, b Transaction) (string, bool) {
+	aOrig, bOrig := a.OriginalAmount.Currency, b.OriginalAmount.Currency
+	aAcct, bAcct := a.Amount.Currency, b.Amount.Currency
+
+	switch {
+	case aOrig != "" && aOrig == bOrig:
+		return aOrig, true
+	case aAcct == bAcct:
+		return aAcct, true
+	}
+
+	var candidates []string
+	if aOrig != "" && aOrig == bAcct {
+		candidates = append(candidates, aOrig)
+	}
+	if bOrig != "" && bOrig == aAcct {
+		candidates = append(candidates, bOrig)
+	}
+	if len(candidates) == 0 {
+		return "", false
+	}
+	// Keep the choice independent of argument order
+	return slices.Min(candidates), true
+}
+
+// amountIn returns the transaction's amount in the given currency, which
+// must be either its account or its original currency. With gross set, the
+// account-currency amount includes the fee; the original amount never does,
+// since fees are charged in the account's currency.
+func (t Transaction) amountIn(currency string, gross bool) Money {
+	if t.OriginalAmount.Currency == currency && t.Amount.Currency != currency {
+		return t.OriginalAmount
+	}
+	if gross {
+		return t.Gross()
+	}
+	return t.Amount
+}
+
+// amountDiff returns the absolute difference between the absolute values of
+// two amounts in the same currency.
+func amountDiff(a, b Money) Money {
+	return a.Abs().Sub(b.Abs()).Abs()
+}
+
+// indexPair holds the positions of two matched transactions, with i < j.
+type indexPair struct {
+	i, j int
+}
+
+func newIndexPair(a, b int) indexPair {
+	if a > b {
+		a, b = b, a
+	}
+	return indexPair{i: a, j: b}
+}
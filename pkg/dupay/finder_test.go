@@ -0,0 +1,478 @@
+package dupay
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDeduplicateTransactions(t *testing.T) {
+	baseTime := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		input    []Transaction
+		expected int
+	}{
+		{
+			name:     "empty input",
+			input:    []Transaction{},
+			expected: 0,
+		},
+		{
+			name: "no duplicates",
+			input: []Transaction{
+				{Bank: "BankA", DateTime: baseTime, Amount: NewMoney(-10000, "KGS")},
+				{Bank: "BankA", DateTime: baseTime.Add(time.Hour), Amount: NewMoney(-20000, "KGS")},
+			},
+			expected: 2,
+		},
+		{
+			name: "exact duplicates same bank",
+			input: []Transaction{
+				{Bank: "BankA", DateTime: baseTime, Amount: NewMoney(-10000, "KGS")},
+				{Bank: "BankA", DateTime: baseTime, Amount: NewMoney(-10000, "KGS")},
+			},
+			expected: 1,
+		},
+		{
+			name: "same time different banks - not duplicates for dedup",
+			input: []Transaction{
+				{Bank: "BankA", DateTime: baseTime, Amount: NewMoney(-10000, "KGS")},
+				{Bank: "BankB", DateTime: baseTime, Amount: NewMoney(-10000, "KGS")},
+			},
+			expected: 2,
+		},
+		{
+			name: "multiple duplicates",
+			input: []Transaction{
+				{Bank: "BankA", DateTime: baseTime, Amount: NewMoney(-10000, "KGS")},
+				{Bank: "BankA", DateTime: baseTime, Amount: NewMoney(-10000, "KGS")},
+				{Bank: "BankA", DateTime: baseTime, Amount: NewMoney(-10000, "KGS")},
+				{Bank: "BankA", DateTime: baseTime.Add(time.Hour), Amount: NewMoney(-20000, "KGS")},
+				{Bank: "BankA", DateTime: baseTime.Add(time.Hour), Amount: NewMoney(-20000, "KGS")},
+			},
+			expected: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := deduplicateTransactions(tt.input)
+			if len(result) != tt.expected {
+				t.Errorf("expected %d transactions, got %d", tt.expected, len(result))
+			}
+		})
+	}
+}
+
+func TestDeduplicateOverlaps(t *testing.T) {
+	baseTime := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+	charge := func(file string) Transaction {
+		return Transaction{Bank: "Mbank", DateTime: baseTime, Amount: NewMoney(-10000, "KGS"), Source: Source{File: file}}
+	}
+
+	tests := []struct {
+		name     string
+		input    []Transaction
+		expected int
+	}{
+		{
+			name:     "same row in two overlapping files",
+			input:    []Transaction{charge("jan.pdf"), charge("q1.pdf")},
+			expected: 1,
+		},
+		{
+			name:     "double charge within one file",
+			input:    []Transaction{charge("jan.pdf"), charge("jan.pdf")},
+			expected: 2,
+		},
+		{
+			name:     "double charge present in both overlapping files",
+			input:    []Transaction{charge("jan.pdf"), charge("jan.pdf"), charge("q1.pdf"), charge("q1.pdf")},
+			expected: 2,
+		},
+		{
+			name:     "file with more repeats wins",
+			input:    []Transaction{charge("jan.pdf"), charge("q1.pdf"), charge("q1.pdf"), charge("q1.pdf")},
+			expected: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := deduplicateOverlaps(tt.input)
+			if len(result) != tt.expected {
+				t.Errorf("expected %d transactions, got %d", tt.expected, len(result))
+			}
+		})
+	}
+}
+
+func TestFindDuplicates(t *testing.T) {
+	baseTime := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		transactions  []Transaction
+		maxTimeDiff   time.Duration
+		maxAmountDiff int64
+		expected      int
+	}{
+		{
+			name:          "empty input",
+			transactions:  []Transaction{},
+			maxTimeDiff:   time.Minute,
+			maxAmountDiff: 100,
+			expected:      0,
+		},
+		{
+			name: "single transaction",
+			transactions: []Transaction{
+				{Bank: "BankA", DateTime: baseTime, Amount: NewMoney(-10000, "KGS")},
+			},
+			maxTimeDiff:   time.Minute,
+			maxAmountDiff: 100,
+			expected:      0,
+		},
+		{
+			name: "same bank - no match",
+			transactions: []Transaction{
+				{Bank: "BankA", DateTime: baseTime, Amount: NewMoney(-10000, "KGS")},
+				{Bank: "BankA", DateTime: baseTime, Amount: NewMoney(-10000, "KGS")},
+			},
+			maxTimeDiff:   time.Minute,
+			maxAmountDiff: 100,
+			expected:      0,
+		},
+		{
+			name: "same bank different accounts",
+			transactions: []Transaction{
+				{Bank: "BankA", Account: "1030********1111", DateTime: baseTime, Amount: NewMoney(-10000, "KGS")},
+				{Bank: "BankA", Account: "1030********2222", DateTime: baseTime, Amount: NewMoney(-10000, "KGS")},
+			},
+			maxTimeDiff:   time.Minute,
+			maxAmountDiff: 100,
+			expected:      1,
+		},
+		{
+			name: "different banks exact match",
+			transactions: []Transaction{
+				{Bank: "BankA", DateTime: baseTime, Amount: NewMoney(-10000, "KGS")},
+				{Bank: "BankB", DateTime: baseTime, Amount: NewMoney(-10000, "KGS")},
+			},
+			maxTimeDiff:   time.Minute,
+			maxAmountDiff: 100,
+			expected:      1,
+		},
+		{
+			name: "different banks within time tolerance",
+			transactions: []Transaction{
+				{Bank: "BankA", DateTime: baseTime, Amount: NewMoney(-10000, "KGS")},
+				{Bank: "BankB", DateTime: baseTime.Add(30 * time.Second), Amount: NewMoney(-10000, "KGS")},
+			},
+			maxTimeDiff:   time.Minute,
+			maxAmountDiff: 100,
+			expected:      1,
+		},
+		{
+			name: "different banks outside time tolerance",
+			transactions: []Transaction{
+				{Bank: "BankA", DateTime: baseTime, Amount: NewMoney(-10000, "KGS")},
+				{Bank: "BankB", DateTime: baseTime.Add(2 * time.Minute), Amount: NewMoney(-10000, "KGS")},
+			},
+			maxTimeDiff:   time.Minute,
+			maxAmountDiff: 100,
+			expected:      0,
+		},
+		{
+			name: "different banks within amount tolerance",
+			transactions: []Transaction{
+				{Bank: "BankA", DateTime: baseTime, Amount: NewMoney(-10000, "KGS")},
+				{Bank: "BankB", DateTime: baseTime, Amount: NewMoney(-10050, "KGS")},
+			},
+			maxTimeDiff:   time.Minute,
+			maxAmountDiff: 100,
+			expected:      1,
+		},
+		{
+			name: "different banks outside amount tolerance",
+			transactions: []Transaction{
+				{Bank: "BankA", DateTime: baseTime, Amount: NewMoney(-10000, "KGS")},
+				{Bank: "BankB", DateTime: baseTime, Amount: NewMoney(-10500, "KGS")},
+			},
+			maxTimeDiff:   time.Minute,
+			maxAmountDiff: 100,
+			expected:      0,
+		},
+		{
+			name: "different currencies - no match",
+			transactions: []Transaction{
+				{Bank: "BankA", DateTime: baseTime, Amount: NewMoney(-10000, "KGS")},
+				{Bank: "BankB", DateTime: baseTime, Amount: NewMoney(-10000, "USD")},
+			},
+			maxTimeDiff:   time.Minute,
+			maxAmountDiff: 100,
+			expected:      0,
+		},
+		{
+			name: "credit transactions - no match",
+			transactions: []Transaction{
+				{Bank: "BankA", DateTime: baseTime, Amount: NewMoney(10000, "KGS")},
+				{Bank: "BankB", DateTime: baseTime, Amount: NewMoney(10000, "KGS")},
+			},
+			maxTimeDiff:   time.Minute,
+			maxAmountDiff: 100,
+			expected:      0,
+		},
+		{
+			name: "mixed credit and debit - no match",
+			transactions: []Transaction{
+				{Bank: "BankA", DateTime: baseTime, Amount: NewMoney(-10000, "KGS")},
+				{Bank: "BankB", DateTime: baseTime, Amount: NewMoney(10000, "KGS")},
+			},
+			maxTimeDiff:   time.Minute,
+			maxAmountDiff: 100,
+			expected:      0,
+		},
+		{
+			name: "foreign purchase matched by original amount",
+			transactions: []Transaction{
+				{Bank: "BankA", DateTime: baseTime, Amount: NewMoney(-131250, "KGS"), OriginalAmount: NewMoney(-1500, "USD")},
+				{Bank: "BankB", DateTime: baseTime, Amount: NewMoney(-132000, "KGS"), OriginalAmount: NewMoney(-1500, "USD")},
+			},
+			maxTimeDiff:   time.Minute,
+			maxAmountDiff: 100,
+			expected:      1,
+		},
+		{
+			name: "foreign purchase against a USD account",
+			transactions: []Transaction{
+				{Bank: "BankA", DateTime: baseTime, Amount: NewMoney(-131250, "KGS"), OriginalAmount: NewMoney(-1500, "USD")},
+				{Bank: "BankB", DateTime: baseTime, Amount: NewMoney(-1500, "USD")},
+			},
+			maxTimeDiff:   time.Minute,
+			maxAmountDiff: 100,
+			expected:      1,
+		},
+		{
+			name: "foreign purchases in different original currencies",
+			transactions: []Transaction{
+				{Bank: "BankA", DateTime: baseTime, Amount: NewMoney(-131250, "KGS"), OriginalAmount: NewMoney(-1500, "USD")},
+				{Bank: "BankB", DateTime: baseTime, Amount: NewMoney(-131250, "KGS"), OriginalAmount: NewMoney(-1400, "EUR")},
+			},
+			maxTimeDiff:   time.Minute,
+			maxAmountDiff: 100,
+			expected:      1,
+		},
+		{
+			name: "multiple potential duplicates",
+			transactions: []Transaction{
+				{Bank: "BankA", DateTime: baseTime, Amount: NewMoney(-10000, "KGS")},
+				{Bank: "BankB", DateTime: baseTime, Amount: NewMoney(-10000, "KGS")},
+				{Bank: "BankA", DateTime: baseTime.Add(time.Hour), Amount: NewMoney(-20000, "KGS")},
+				{Bank: "BankB", DateTime: baseTime.Add(time.Hour), Amount: NewMoney(-20000, "KGS")},
+			},
+			maxTimeDiff:   time.Minute,
+			maxAmountDiff: 100,
+			expected:      2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := FindDuplicates(tt.transactions, tt.maxTimeDiff, tt.maxAmountDiff)
+			if len(result) != tt.expected {
+				t.Errorf("expected %d duplicates, got %d", tt.expected, len(result))
+			}
+		})
+	}
+}
+
+func TestFindDuplicatesMatchDetails(t *testing.T) {
+	baseTime := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	transactions := []Transaction{
+		{Bank: "Optima", DateTime: baseTime, Amount: NewMoney(-10000, "KGS"), Description: "Payment 1"},
+		{Bank: "Mbank", DateTime: baseTime.Add(30 * time.Second), Amount: NewMoney(-10050, "KGS"), Description: "Payment 1"},
+	}
+
+	result := FindDuplicates(transactions, time.Minute, 100)
+
+	if len(result) != 1 {
+		t.Fatalf("expected 1 duplicate, got %d", len(result))
+	}
+
+	match := result[0]
+
+	// Verify time difference
+	expectedTimeDiff := 30 * time.Second
+	if match.TimeDiff != expectedTimeDiff {
+		t.Errorf("expected time diff %v, got %v", expectedTimeDiff, match.TimeDiff)
+	}
+
+	// Verify amount difference
+	expectedAmountDiff := NewMoney(50, "KGS")
+	if match.AmountDiff != expectedAmountDiff {
+		t.Errorf("expected amount diff %v, got %v", expectedAmountDiff, match.AmountDiff)
+	}
+
+	// Verify banks are different
+	if match.Transaction1.Bank == match.Transaction2.Bank {
+		t.Error("matched transactions should be from different banks")
+	}
+	if match.Kind != CrossBank {
+		t.Errorf("expected kind %q, got %q", CrossBank, match.Kind)
+	}
+}
+
+// findDuplicatesPairwise is the original O(n²) finder, kept as a reference
+// implementation for the sort-and-sweep version.
+func TestFindDuplicatesComparesOriginalAmount(t *testing.T) {
+	baseTime := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+	transactions := []Transaction{
+		{Bank: "BankA", DateTime: baseTime, Amount: NewMoney(-131250, "KGS"), OriginalAmount: NewMoney(-1500, "USD")},
+		{Bank: "BankB", DateTime: baseTime, Amount: NewMoney(-135000, "KGS"), OriginalAmount: NewMoney(-1500, "USD")},
+	}
+
+	// The KGS amounts differ by 37.50 because of the banks' rates, but the
+	// USD amounts are identical
+	result := FindDuplicates(transactions, time.Minute, 100)
+	if len(result) != 1 {
+		t.Fatalf("expected 1 match, got %d", len(result))
+	}
+	if expected := NewMoney(0, "USD"); result[0].AmountDiff != expected {
+		t.Errorf("expected amount diff %v, got %v", expected, result[0].AmountDiff)
+	}
+}
+
+func findDuplicatesPairwise(transactions []Transaction, maxTimeDiff time.Duration, maxAmountDiff int64) []DuplicateMatch {
+	transactions = deduplicateTransactions(transactions)
+
+	var matches []DuplicateMatch
+	for i := 0; i < len(transactions); i++ {
+		for j := i + 1; j < len(transactions); j++ {
+			t1 := transactions[i]
+			t2 := transactions[j]
+
+			currency, ok := commonCurrency(t1, t2)
+			if t1.Bank == t2.Bank || !ok {
+				continue
+			}
+			if !t1.Amount.IsNegative() || !t2.Amount.IsNegative() {
+				continue
+			}
+
+			timeDiff := t1.DateTime.Sub(t2.DateTime)
+			if timeDiff < 0 {
+				timeDiff = -timeDiff
+			}
+			if timeDiff > maxTimeDiff {
+				continue
+			}
+
+			amountDiff := t1.amountIn(currency, false).Abs().Sub(t2.amountIn(currency, false).Abs()).Abs()
+			if amountDiff.Minor > maxAmountDiff {
+				continue
+			}
+
+			match := DuplicateMatch{
+				Kind:         CrossBank,
+				Transaction1: t1,
+				Transaction2: t2,
+				TimeDiff:     timeDiff,
+				AmountDiff:   amountDiff,
+			}
+			match.Confidence = confidence(match, false, Options{MaxTimeDiff: maxTimeDiff, MaxAmountDiff: maxAmountDiff})
+			matches = append(matches, match)
+		}
+	}
+
+	return matches
+}
+
+// generateTransactions builds a deterministic set of transactions spread over
+// a year across three banks, with a share of near-identical charges.
+func generateTransactions(n int, seed uint64) []Transaction {
+	rng := rand.New(rand.NewPCG(seed, seed))
+	banks := []string{"Optima Bank", "Mbank", "Demir Bank"}
+	currencies := []string{"KGS", "KGS", "KGS", "USD"}
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	year := int64(365 * 24 * time.Hour / time.Minute)
+
+	transactions := make([]Transaction, 0, n)
+	for len(transactions) < n {
+		t := Transaction{
+			DateTime: start.Add(time.Duration(rng.Int64N(year)) * time.Minute),
+			Amount:   NewMoney(-rng.Int64N(500000), currencies[rng.IntN(len(currencies))]),
+			Bank:     banks[rng.IntN(len(banks))],
+		}
+		if rng.IntN(10) == 0 {
+			t.Amount = t.Amount.Neg()
+		}
+		transactions = append(transactions, t)
+
+		// Every so often, charge the same payment on another card
+		if rng.IntN(20) == 0 && len(transactions) < n {
+			dup := t
+			dup.Bank = banks[rng.IntN(len(banks))]
+			dup.DateTime = t.DateTime.Add(time.Duration(rng.IntN(90)) * time.Second)
+			dup.Amount = t.Amount.Sub(NewMoney(rng.Int64N(150), t.Amount.Currency))
+			transactions = append(transactions, dup)
+		}
+	}
+
+	return transactions
+}
+
+func TestFindDuplicatesMatchesPairwise(t *testing.T) {
+	tests := []struct {
+		name          string
+		n             int
+		maxTimeDiff   time.Duration
+		maxAmountDiff int64
+	}{
+		{name: "small", n: 200, maxTimeDiff: time.Minute, maxAmountDiff: 100},
+		{name: "wide window", n: 2000, maxTimeDiff: 12 * time.Hour, maxAmountDiff: 500},
+		{name: "zero tolerance", n: 2000, maxTimeDiff: 0, maxAmountDiff: 0},
+		{name: "default tolerance", n: 5000, maxTimeDiff: time.Minute, maxAmountDiff: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transactions := generateTransactions(tt.n, uint64(tt.n))
+
+			expected := findDuplicatesPairwise(transactions, tt.maxTimeDiff, tt.maxAmountDiff)
+			result := FindDuplicates(transactions, tt.maxTimeDiff, tt.maxAmountDiff)
+
+			if !reflect.DeepEqual(result, expected) {
+				t.Errorf("sort-and-sweep result differs from pairwise: got %d matches, expected %d", len(result), len(expected))
+			}
+		})
+	}
+}
+
+func BenchmarkFindDuplicates(b *testing.B) {
+	for _, n := range []int{1000, 10000, 100000, 250000} {
+		transactions := generateTransactions(n, 42)
+		b.Run(fmt.Sprintf("sweep/n=%d", n), func(b *testing.B) {
+			for b.Loop() {
+				FindDuplicates(transactions, time.Minute, 100)
+			}
+		})
+	}
+}
+
+func BenchmarkFindDuplicatesPairwise(b *testing.B) {
+	// The pairwise reference is quadratic, so larger sizes take minutes per iteration
+	for _, n := range []int{1000, 10000} {
+		transactions := generateTransactions(n, 42)
+		b.Run(fmt.Sprintf("pairwise/n=%d", n), func(b *testing.B) {
+			for b.Loop() {
+				findDuplicatesPairwise(transactions, time.Minute, 100)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package dupay
+
+import (
+	"maps"
+	"slices"
+	"sort"
+	"time"
+)
+
+// DuplicateGroup is one payment charged several times: the transactions
+// linked by duplicate matches, directly or through other members. When the
+// same payment appears in three statements, the three pairwise matches form
+// a single group.
+type DuplicateGroup struct {
+	// Kind is DoubleCharge when every match is within one statement, and
+	// CrossBank otherwise.
+	Kind MatchKind
+	// Transactions holds the members in chronological order.
+	Transactions []Transaction
+	// Matches holds the pairwise matches that link the members.
+	Matches []DuplicateMatch
+	// Currency is the currency the members' amounts are compared in.
+	Currency string
+	// Amounts holds the amount of each member in Currency, aligned with
+	// Transactions. It is zero for a member that cannot be expressed in
+	// Currency, which is left out of AmountSpread and Extra.
+	Amounts []Money
+	// TimeSpread is the time between the earliest and the latest member.
+	TimeSpread time.Duration
+	// AmountSpread is the difference between the largest and the smallest
+	// absolute amount, in Currency.
+	AmountSpread Money
+	// Extra is the amount charged beyond the intended payment: every member
+	// but one is an extra charge, each counted at the members' average
+	// amount.
+	Extra Money
+	// Confidence is the highest confidence of the group's matches.
+	Confidence int
+}
+
+// GroupDuplicates merges matches that share a transaction into groups.
+// Amounts are compared as opts configures the search: including fees with
+// CompareGross, and converted with Rates. Groups are ordered from the most
+// to the least confident, and groups of the same confidence by their first
+// match.
+func GroupDuplicates(matches []DuplicateMatch, opts Options) []DuplicateGroup {
+	// Union-find over the transactions; identical values are the same
+	// transaction, since every transaction carries its own source line
+	index := make(map[Transaction]int)
+	var parent []int
+	id := func(t Transaction) int {
+		i, ok := index[t]
+		if !ok {
+			i = len(parent)
+			index[t] = i
+			parent = append(parent, i)
+		}
+		return i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	for _, m := range matches {
+		a, b := find(id(m.Transaction1)), find(id(m.Transaction2))
+		if a != b {
+			parent[max(a, b)] = min(a, b)
+		}
+	}
+
+	var groups []DuplicateGroup
+	position := make(map[int]int)
+	for _, m := range matches {
+		root := find(index[m.Transaction1])
+		pos, ok := position[root]
+		if !ok {
+			pos = len(groups)
+			position[root] = pos
+			groups = append(groups, DuplicateGroup{})
+		}
+		g := &groups[pos]
+		g.Matches = append(g.Matches, m)
+		for _, t := range []Transaction{m.Transaction1, m.Transaction2} {
+			if !slices.Contains(g.Transactions, t) {
+				g.Transactions = append(g.Transactions, t)
+			}
+		}
+	}
+
+	for i := range groups {
+		groups[i].summarize(opts)
+	}
+	slices.SortStableFunc(groups, func(a, b DuplicateGroup) int {
+		return b.Confidence - a.Confidence
+	})
+	return groups
+}
+
+// summarize fills in everything derived from the group's matches.
+func (g *DuplicateGroup) summarize(opts Options) {
+	sort.SliceStable(g.Transactions, func(a, b int) bool {
+		return g.Transactions[a].DateTime.Before(g.Transactions[b].DateTime)
+	})
+	first, last := g.Transactions[0], g.Transactions[len(g.Transactions)-1]
+	g.TimeSpread = last.DateTime.Sub(first.DateTime)
+
+	g.Kind = DoubleCharge
+	for _, m := range g.Matches {
+		if m.Kind != DoubleCharge {
+			g.Kind = CrossBank
+		}
+		g.Confidence = max(g.Confidence, m.Confidence)
+	}
+
+	g.Currency = g.pickCurrency(opts)
+	g.Amounts = make([]Money, len(g.Transactions))
+	var sum, smallest, largest int64
+	known := 0
+	for i, t := range g.Transactions {
+		amount, ok := g.amountIn(t, g.Currency, opts)
+		if !ok {
+			continue
+		}
+		g.Amounts[i] = amount
+		abs := absMinor(amount.Minor)
+		if known == 0 || abs < smallest {
+			smallest = abs
+		}
+		if known == 0 || abs > largest {
+			largest = abs
+		}
+		sum += amount.Minor
+		known++
+	}
+
+	g.AmountSpread = NewMoney(largest-smallest, g.Currency)
+	g.Extra = NewMoney(0, g.Currency)
+	if known > 0 {
+		g.Extra = NewMoney(scaleMinor(sum, int64(len(g.Transactions)-1), int64(known)), g.Currency)
+	}
+}
+
+// pickCurrency chooses the currency the members are compared in: the one
+// most matches were compared in that every member can be expressed in. When
+// there is none, the most used one is taken anyway.
+func (g *DuplicateGroup) pickCurrency(opts Options) string {
+	counts := make(map[string]int)
+	for _, m := range g.Matches {
+		counts[m.AmountDiff.Currency]++
+	}
+	candidates := slices.Sorted(maps.Keys(counts))
+	sort.SliceStable(candidates, func(a, b int) bool {
+		return counts[candidates[a]] > counts[candidates[b]]
+	})
+
+	for _, currency := range candidates {
+		all := true
+		for _, t := range g.Transactions {
+			if _, ok := g.amountIn(t, currency, opts); !ok {
+				all = false
+				break
+			}
+		}
+		if all {
+			return currency
+		}
+	}
+	return candidates[0]
+}
+
+// amountIn returns the amount of member t in currency: its own amount or
+// original amount, the amount a match converted it to, or a conversion at
+// the exchange rates.
+func (g *DuplicateGroup) amountIn(t Transaction, currency string, opts Options) (Money, bool) {
+	if t.Amount.Currency == currency || t.OriginalAmount.Currency == currency {
+		return t.amountIn(currency, opts.CompareGross), true
+	}
+	for _, m := range g.Matches {
+		c := m.Conversion
+		switch {
+		case c == nil || c.Currency != currency:
+		case m.Transaction1 == t:
+			return c.Amount1, true
+		case m.Transaction2 == t:
+			return c.Amount2, true
+		}
+	}
+	if opts.Rates != nil {
+		return opts.Rates.Convert(t.amountIn(t.Amount.Currency, opts.CompareGross), currency, t.DateTime)
+	}
+	return Money{}, false
+}
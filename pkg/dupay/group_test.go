@@ -0,0 +1,133 @@
+package dupay
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGroupDuplicates(t *testing.T) {
+	base := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	tx := func(bank string, offset time.Duration, amount Money) Transaction {
+		return Transaction{Bank: bank, DateTime: base.Add(offset), Amount: amount}
+	}
+	match := func(kind MatchKind, t1, t2 Transaction) DuplicateMatch {
+		return DuplicateMatch{Kind: kind, Transaction1: t1, Transaction2: t2, AmountDiff: amountDiff(t1.Amount, t2.Amount)}
+	}
+
+	optima := tx("Optima Bank", time.Minute, NewMoney(-10000, "KGS"))
+	mbank := tx("Mbank", 0, NewMoney(-10050, "KGS"))
+	demir := tx("Demir", 2*time.Minute, NewMoney(-10000, "KGS"))
+	first := Transaction{Bank: "Mbank", DateTime: base.Add(time.Hour), Amount: NewMoney(-500, "KGS"), Source: Source{File: "mbank.pdf", StartLine: 1}}
+	second := Transaction{Bank: "Mbank", DateTime: base.Add(time.Hour), Amount: NewMoney(-500, "KGS"), Source: Source{File: "mbank.pdf", StartLine: 2}}
+
+	matches := []DuplicateMatch{
+		match(CrossBank, optima, mbank),
+		match(DoubleCharge, first, second),
+		// Linked to the first group only through mbank
+		match(CrossBank, mbank, demir),
+	}
+
+	groups := GroupDuplicates(matches, DefaultOptions())
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 groups, got %d", len(groups))
+	}
+
+	g := groups[0]
+	if g.Kind != CrossBank {
+		t.Errorf("expected kind %s, got %s", CrossBank, g.Kind)
+	}
+	if expected := []Transaction{mbank, optima, demir}; !reflect.DeepEqual(g.Transactions, expected) {
+		t.Errorf("expected members in chronological order %+v, got %+v", expected, g.Transactions)
+	}
+	if expected := []DuplicateMatch{matches[0], matches[2]}; !reflect.DeepEqual(g.Matches, expected) {
+		t.Errorf("expected matches %+v, got %+v", expected, g.Matches)
+	}
+	if g.Currency != "KGS" {
+		t.Errorf("expected currency KGS, got %q", g.Currency)
+	}
+	if g.TimeSpread != 2*time.Minute {
+		t.Errorf("expected time spread 2m, got %v", g.TimeSpread)
+	}
+	if expected := NewMoney(50, "KGS"); g.AmountSpread != expected {
+		t.Errorf("expected amount spread %s, got %s", expected, g.AmountSpread)
+	}
+	// Two extra charges at the average of -100.1666...
+	if expected := NewMoney(-20033, "KGS"); g.Extra != expected {
+		t.Errorf("expected extra %s, got %s", expected, g.Extra)
+	}
+
+	g = groups[1]
+	if g.Kind != DoubleCharge || len(g.Transactions) != 2 {
+		t.Errorf("expected a double charge of 2 transactions, got %s with %d", g.Kind, len(g.Transactions))
+	}
+	if expected := NewMoney(-500, "KGS"); g.Extra != expected {
+		t.Errorf("expected extra %s, got %s", expected, g.Extra)
+	}
+}
+
+func TestGroupDuplicatesCurrencies(t *testing.T) {
+	base := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	rates := NewExchangeRates("KGS")
+	rates.Add(base, "USD", 87.5)
+
+	// Two USD card payments compared in USD, and the second one matched
+	// with a KGS payment after conversion
+	card1 := Transaction{Bank: "Optima Bank", DateTime: base, Amount: NewMoney(-131250, "KGS"), OriginalAmount: NewMoney(-1500, "USD")}
+	card2 := Transaction{Bank: "Mbank", DateTime: base, Amount: NewMoney(-1500, "USD")}
+	local := Transaction{Bank: "Demir", DateTime: base.Add(time.Minute), Amount: NewMoney(-131000, "KGS")}
+
+	matches := []DuplicateMatch{
+		{Kind: CrossBank, Transaction1: card1, Transaction2: card2, AmountDiff: NewMoney(0, "USD")},
+		{
+			Kind:         CrossBank,
+			Transaction1: card2,
+			Transaction2: local,
+			AmountDiff:   NewMoney(250, "KGS"),
+			Conversion:   &Conversion{Currency: "KGS", Amount1: NewMoney(-131250, "KGS"), Amount2: NewMoney(-131000, "KGS")},
+		},
+	}
+
+	opts := DefaultOptions()
+	opts.Rates = rates
+	groups := GroupDuplicates(matches, opts)
+	if len(groups) != 1 {
+		t.Fatalf("expected 1 group, got %d", len(groups))
+	}
+
+	// local has no USD amount, so the members are compared in KGS
+	g := groups[0]
+	if g.Currency != "KGS" {
+		t.Fatalf("expected currency KGS, got %q", g.Currency)
+	}
+	expected := []Money{NewMoney(-131250, "KGS"), NewMoney(-131250, "KGS"), NewMoney(-131000, "KGS")}
+	if !reflect.DeepEqual(g.Amounts, expected) {
+		t.Errorf("expected amounts %v, got %v", expected, g.Amounts)
+	}
+	if g.AmountSpread != NewMoney(250, "KGS") {
+		t.Errorf("expected amount spread 2.50 KGS, got %s", g.AmountSpread)
+	}
+}
+
+func TestAnalyzeGroupsPaymentInThreeStatements(t *testing.T) {
+	base := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	transactions := []Transaction{
+		{Bank: "Optima Bank", DateTime: base, Amount: NewMoney(-10000, "KGS")},
+		{Bank: "Mbank", DateTime: base.Add(20 * time.Second), Amount: NewMoney(-10000, "KGS")},
+		{Bank: "Demir", DateTime: base.Add(40 * time.Second), Amount: NewMoney(-10000, "KGS")},
+	}
+
+	result := Analyze(transactions, DefaultOptions())
+	if len(result.Duplicates) != 3 {
+		t.Fatalf("expected 3 pairwise matches, got %d", len(result.Duplicates))
+	}
+	if len(result.Groups) != 1 || len(result.Groups[0].Transactions) != 3 {
+		t.Fatalf("expected 1 group of 3 transactions, got %+v", result.Groups)
+	}
+
+	report := &Report{Duplicates: result.Duplicates, Options: DefaultOptions()}
+	expected := []Money{NewMoney(-20000, "KGS")}
+	if totals := report.TotalDuplicateAmounts(); !reflect.DeepEqual(totals, expected) {
+		t.Errorf("expected the two extra charges %v, got %v", expected, totals)
+	}
+}
@@ -0,0 +1,172 @@
+package dupay
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// ErrNoTable is returned by LayoutParser.ParseLayout when the rows hold no
+// transaction table the parser recognizes. Callers fall back to Parse.
+var ErrNoTable = errors.New("no transaction table found")
+
+// Row is a line of text on a PDF page: the glyphs printed on the same
+// baseline, split into cells wherever a gap shows a column break.
+type Row struct {
+	// Page is the 1-based PDF page the row is printed on.
+	Page int
+	// Y is the row's baseline in points, increasing bottom to top.
+	Y float64
+	// Cells holds the row's cells from left to right.
+	Cells []Cell
+}
+
+// Cell is a run of text within a row.
+type Cell struct {
+	// X is the left edge of the cell in points.
+	X float64
+	// Text is the cell's text with runs of whitespace collapsed.
+	Text string
+}
+
+// Text returns the cells of the row separated by spaces.
+func (r Row) Text() string {
+	texts := make([]string, len(r.Cells))
+	for i, c := range r.Cells {
+		texts[i] = c.Text
+	}
+	return strings.Join(texts, " ")
+}
+
+// columnTolerance is how far in points a cell may start left of its column,
+// for text that is not aligned exactly with the header.
+const columnTolerance = 5
+
+// Columns are the left edges of a table's columns in points, in order,
+// usually taken from the cells of its header row.
+type Columns []float64
+
+// Split returns the text of row in each column. A cell belongs to the last
+// column starting left of it; cells left of the first column belong to it.
+// Several cells in one column are joined by spaces.
+func (c Columns) Split(r Row) []string {
+	texts := make([]string, len(c))
+	if len(c) == 0 {
+		return texts
+	}
+	for _, cell := range r.Cells {
+		col := 0
+		for i, x := range c {
+			if cell.X+columnTolerance >= x {
+				col = i
+			}
+		}
+		if texts[col] != "" {
+			texts[col] += " "
+		}
+		texts[col] += cell.Text
+	}
+	return texts
+}
+
+const (
+	// rowTolerance is how far apart in points two baselines may be and
+	// still belong to one row, as a fraction of the font size.
+	rowTolerance = 0.3
+	// spaceGap is the smallest gap between glyphs, as a fraction of the
+	// font size, that separates two words when the PDF draws no space.
+	spaceGap = 0.2
+	// cellGap is the smallest gap between glyphs, as a fraction of the font
+	// size, that separates two cells.
+	cellGap = 1.0
+	// defaultFontSize is assumed for glyphs that report no font size.
+	defaultFontSize = 10
+)
+
+// pageRows reads the rows of a page from the position and width of every
+// glyph. The reader's own GetTextByRow ignores relative text moves and
+// reports no widths, which merges the columns of a table.
+func pageRows(p pdf.Page, number int) (rows []Row, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			rows, err = nil, fmt.Errorf("reading layout of page %d: %v", number, r)
+		}
+	}()
+	return buildRows(number, p.Content().Text), nil
+}
+
+// buildRows groups glyphs into rows from the top of the page down, and the
+// glyphs of each row into cells.
+func buildRows(page int, glyphs []pdf.Text) []Row {
+	glyphs = slices.Clone(glyphs)
+	slices.SortStableFunc(glyphs, func(a, b pdf.Text) int {
+		return cmp.Compare(b.Y, a.Y)
+	})
+
+	var rows []Row
+	for start := 0; start < len(glyphs); {
+		end := start + 1
+		for end < len(glyphs) && glyphs[start].Y-glyphs[end].Y <= rowTolerance*fontSize(glyphs[start]) {
+			end++
+		}
+		if row := buildRow(page, glyphs[start].Y, glyphs[start:end]); len(row.Cells) > 0 {
+			rows = append(rows, row)
+		}
+		start = end
+	}
+	return rows
+}
+
+// buildRow joins the glyphs of one row into cells. Whitespace glyphs don't
+// extend a cell, so columns padded with spaces are still split.
+func buildRow(page int, y float64, glyphs []pdf.Text) Row {
+	glyphs = slices.Clone(glyphs)
+	slices.SortStableFunc(glyphs, func(a, b pdf.Text) int {
+		return cmp.Compare(a.X, b.X)
+	})
+
+	row := Row{Page: page, Y: y}
+	var text strings.Builder
+	var x, end float64
+	flush := func() {
+		if s := strings.Join(strings.Fields(text.String()), " "); s != "" {
+			row.Cells = append(row.Cells, Cell{X: x, Text: s})
+		}
+		text.Reset()
+	}
+
+	for _, g := range glyphs {
+		if strings.TrimSpace(g.S) == "" {
+			if text.Len() > 0 {
+				text.WriteString(" ")
+			}
+			continue
+		}
+
+		size := fontSize(g)
+		switch gap := g.X - end; {
+		case text.Len() == 0:
+			x = g.X
+		case gap >= cellGap*size:
+			flush()
+			x = g.X
+		case gap >= spaceGap*size:
+			text.WriteString(" ")
+		}
+		text.WriteString(g.S)
+		end = g.X + g.W
+	}
+	flush()
+	return row
+}
+
+func fontSize(g pdf.Text) float64 {
+	if g.FontSize > 0 {
+		return g.FontSize
+	}
+	return defaultFontSize
+}
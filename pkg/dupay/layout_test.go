@@ -0,0 +1,100 @@
+package dupay
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// glyphs lays out text one glyph per character, 5 points wide, starting at x.
+func glyphs(x, y float64, text string) []pdf.Text {
+	var result []pdf.Text
+	for _, r := range text {
+		result = append(result, pdf.Text{FontSize: 10, X: x, Y: y, W: 5, S: string(r)})
+		x += 5
+	}
+	return result
+}
+
+func TestBuildRows(t *testing.T) {
+	var page []pdf.Text
+	// Lower rows first: rows are ordered by position, not by drawing order
+	page = append(page, glyphs(40, 680, "10:30")...)
+	page = append(page, glyphs(40, 700, "15.01.2025")...)
+	page = append(page, glyphs(120, 700.5, "Payment to")...)
+	page = append(page, glyphs(300, 700, "-1 500.00")...)
+	page = append(page, glyphs(350, 699.8, "KGS")...)
+	// Words drawn without a space glyph are still separated
+	page = append(page, glyphs(120, 680, "Globus")...)
+	page = append(page, glyphs(152, 680, "Bishkek")...)
+	// Whitespace between columns does not join them
+	page = append(page, glyphs(200, 660, "Total      25.00")...)
+
+	expected := []Row{
+		{Page: 2, Y: 700.5, Cells: []Cell{
+			{X: 40, Text: "15.01.2025"},
+			{X: 120, Text: "Payment to"},
+			{X: 300, Text: "-1 500.00 KGS"},
+		}},
+		{Page: 2, Y: 680, Cells: []Cell{
+			{X: 40, Text: "10:30"},
+			{X: 120, Text: "Globus Bishkek"},
+		}},
+		{Page: 2, Y: 660, Cells: []Cell{
+			{X: 200, Text: "Total"},
+			{X: 255, Text: "25.00"},
+		}},
+	}
+
+	rows := buildRows(2, page)
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("expected %+v, got %+v", expected, rows)
+	}
+}
+
+func TestColumnsSplit(t *testing.T) {
+	columns := Columns{40, 120, 300}
+
+	tests := []struct {
+		name     string
+		row      Row
+		expected []string
+	}{
+		{
+			name:     "one cell per column",
+			row:      Row{Cells: []Cell{{X: 40, Text: "15.01.2025"}, {X: 120, Text: "Payment"}, {X: 310, Text: "-1 500.00"}}},
+			expected: []string{"15.01.2025", "Payment", "-1 500.00"},
+		},
+		{
+			name:     "empty columns",
+			row:      Row{Cells: []Cell{{X: 320, Text: "KGS"}}},
+			expected: []string{"", "", "KGS"},
+		},
+		{
+			name:     "cells in one column are joined",
+			row:      Row{Cells: []Cell{{X: 120, Text: "Payment"}, {X: 200, Text: "Bishkek"}}},
+			expected: []string{"", "Payment Bishkek", ""},
+		},
+		{
+			name:     "slightly left of the column",
+			row:      Row{Cells: []Cell{{X: 30, Text: "left"}, {X: 297, Text: "-15.00"}}},
+			expected: []string{"left", "", "-15.00"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := columns.Split(tt.row); !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestRowText(t *testing.T) {
+	row := Row{Cells: []Cell{{X: 40, Text: "15.01.2025"}, {X: 120, Text: "Payment"}}}
+	if got := row.Text(); got != "15.01.2025 Payment" {
+		t.Errorf("expected %q, got %q", "15.01.2025 Payment", got)
+	}
+}
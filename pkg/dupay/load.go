@@ -0,0 +1,80 @@
+package dupay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// StatementFile is the outcome of reading one statement file.
+type StatementFile struct {
+	// Result describes how the file was processed, including its error.
+	Result FileResult
+	// Transactions holds the transactions parsed from the file.
+	Transactions []Transaction
+}
+
+// LoadStatements calls read for every path with up to jobs goroutines and
+// returns the files in the order of paths. Errors are reported per file in
+// Result.Err, and a panic while reading a malformed file becomes that file's
+// error instead of stopping the others.
+//
+// When ctx is canceled, files that haven't started are skipped and ctx's
+// error is returned without waiting for the files in progress, since PDF
+// extraction can't be interrupted.
+func LoadStatements(ctx context.Context, paths []string, jobs int, read func(path string) StatementFile) ([]StatementFile, error) {
+	if jobs < 1 {
+		return nil, errors.New("jobs must be at least 1")
+	}
+
+	files := make([]StatementFile, len(paths))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for range min(jobs, len(paths)) {
+		wg.Go(func() {
+			for i := range next {
+				// A file handed out as ctx was canceled is skipped too
+				if ctx.Err() != nil {
+					continue
+				}
+				files[i] = readRecovered(paths[i], read)
+			}
+		})
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer wg.Wait()
+		defer close(next)
+		for i := range paths {
+			select {
+			case next <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	select {
+	case <-done:
+		// Files skipped because of a late cancellation are left empty
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return files, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// readRecovered calls read, turning a panic into the file's error.
+func readRecovered(path string, read func(path string) StatementFile) (f StatementFile) {
+	defer func() {
+		if r := recover(); r != nil {
+			f = StatementFile{Result: FileResult{Path: path, Err: fmt.Errorf("processing: %v", r)}}
+		}
+	}()
+	return read(path)
+}
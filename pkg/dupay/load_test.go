@@ -0,0 +1,120 @@
+package dupay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLoadStatementsKeepsOrder(t *testing.T) {
+	var paths []string
+	for i := range 8 {
+		paths = append(paths, fmt.Sprintf("statement-%d.pdf", i))
+	}
+
+	// Later files finish first, so results arrive out of order
+	files, err := LoadStatements(context.Background(), paths, 4, func(path string) StatementFile {
+		var i int
+		fmt.Sscanf(path, "statement-%d.pdf", &i)
+		time.Sleep(time.Duration(len(paths)-i) * time.Millisecond)
+		return StatementFile{Result: FileResult{Path: path, Transactions: i}}
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(files) != len(paths) {
+		t.Fatalf("expected %d files, got %d", len(paths), len(files))
+	}
+	for i, f := range files {
+		if f.Result.Path != paths[i] || f.Result.Transactions != i {
+			t.Errorf("file %d: expected %s, got %+v", i, paths[i], f.Result)
+		}
+	}
+}
+
+func TestLoadStatementsIsolatesFileErrors(t *testing.T) {
+	paths := []string{"ok-1.pdf", "panic.pdf", "error.pdf", "ok-2.pdf"}
+	errRead := errors.New("reading PDF: malformed")
+
+	files, err := LoadStatements(context.Background(), paths, 2, func(path string) StatementFile {
+		switch path {
+		case "panic.pdf":
+			panic("index out of range")
+		case "error.pdf":
+			return StatementFile{Result: FileResult{Path: path, Err: errRead}}
+		}
+		return StatementFile{
+			Result:       FileResult{Path: path, Bank: "Mbank", Transactions: 1},
+			Transactions: []Transaction{{Bank: "Mbank", Amount: NewMoney(-100, "KGS")}},
+		}
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, i := range []int{0, 3} {
+		if files[i].Result.Err != nil || len(files[i].Transactions) != 1 {
+			t.Errorf("expected %s to be read despite the other files, got %+v", paths[i], files[i])
+		}
+	}
+	if r := files[1].Result; r.Path != "panic.pdf" || r.Err == nil || !strings.Contains(r.Err.Error(), "index out of range") {
+		t.Errorf("expected the panic as the file's error, got %+v", r)
+	}
+	if r := files[2].Result; !errors.Is(r.Err, errRead) {
+		t.Errorf("expected the read error, got %v", r.Err)
+	}
+}
+
+func TestLoadStatementsCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan string, 3)
+	release := make(chan struct{})
+	var reads atomic.Int32
+
+	type outcome struct {
+		files []StatementFile
+		err   error
+	}
+	done := make(chan outcome)
+	go func() {
+		files, err := LoadStatements(ctx, []string{"a.pdf", "b.pdf", "c.pdf"}, 1, func(path string) StatementFile {
+			reads.Add(1)
+			started <- path
+			<-release
+			return StatementFile{Result: FileResult{Path: path}}
+		})
+		done <- outcome{files, err}
+	}()
+
+	if path := <-started; path != "a.pdf" {
+		t.Fatalf("expected a.pdf to be read first, got %s", path)
+	}
+	cancel()
+
+	// The file in progress is not waited for
+	select {
+	case o := <-done:
+		if !errors.Is(o.err, context.Canceled) || o.files != nil {
+			t.Errorf("expected context.Canceled and no files, got %v and %+v", o.err, o.files)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("LoadStatements waited for the file in progress")
+	}
+
+	close(release)
+	time.Sleep(20 * time.Millisecond)
+	if n := reads.Load(); n != 1 {
+		t.Errorf("expected the pending files to be skipped, got %d reads", n)
+	}
+}
+
+func TestLoadStatementsInvalidJobs(t *testing.T) {
+	read := func(path string) StatementFile { return StatementFile{} }
+	if _, err := LoadStatements(context.Background(), []string{"a.pdf"}, 0, read); err == nil {
+		t.Error("expected an error for zero jobs")
+	}
+}
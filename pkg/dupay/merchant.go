@@ -0,0 +1,238 @@
+package dupay
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// merchantPrefixes are the words banks put before the merchant name, such as
+// Optima's "Оплата товаров и услуг" or Mbank's "Покупка в", transliterated
+// and longest first so the longest one is stripped.
+var merchantPrefixes = splitPhrases(
+	"OPLATA TOVAROV I USLUG",
+	"OPLATA TOVAROV USLUG",
+	"OPLATA USLUG",
+	"OPLATA",
+	"POKUPKA V",
+	"POKUPKA",
+	"SPISANIE",
+	"PAYMENT TO",
+	"PAYMENT",
+	"PURCHASE AT",
+	"PURCHASE",
+	"RETAIL",
+	"POS",
+	"CARD",
+	"KARTA",
+)
+
+// merchantCities are the cities banks print after the merchant name,
+// sometimes after "г." for "город" (city).
+var merchantCities = splitPhrases(
+	"BISHKEK",
+	"OSH",
+	"KARAKOL",
+	"TOKMOK",
+	"KANT",
+	"DZHALAL ABAD",
+	"JALAL ABAD",
+	"CHOLPON ATA",
+	"ALMATY",
+	"MOSCOW",
+	"MOSKVA",
+)
+
+// merchantPlaces are the countries and web domains banks print after the
+// merchant name.
+var merchantPlaces = splitPhrases(
+	"KYRGYZSTAN",
+	"KGZ",
+	"KG",
+	"KAZ",
+	"RUS",
+	"COM",
+	"NET",
+)
+
+// merchantIDWords introduce terminal, merchant and reference numbers.
+var merchantIDWords = map[string]bool{
+	"TID": true, "MID": true, "RRN": true, "REF": true, "TERMINAL": true, "TERM": true,
+}
+
+// cardMask matches masked card numbers such as "4169585*****1234", "**1234"
+// and "4169XXXX1234". Letters only count as a mask next to digits, so names
+// like "EXXON" are kept.
+var cardMask = regexp.MustCompile(`\d*[*•]{2,}\d*|\d+[Xx]{2,}\d*|[Xx]{2,}\d+`)
+
+// NormalizeMerchant extracts the merchant name from a transaction
+// description, so the same shop reads the same in every bank's statement:
+// "Оплата товаров и услуг: ГЛОБУС Бишкек KG" and "Payment to Globus
+// 4169****1234" both become "GLOBUS". The name is transliterated to upper
+// case Latin, and card masks, terminal IDs, bank prefixes and city, country
+// and domain suffixes are removed. It returns "" when nothing is left.
+func NormalizeMerchant(description string) string {
+	text := cardMask.ReplaceAllString(description, " ")
+	text = transliterate(strings.ToUpper(text))
+
+	var words []string
+	skipID := false
+	for _, w := range strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		switch {
+		case merchantIDWords[w]:
+			skipID = true
+			continue
+		case skipID && strings.ContainsFunc(w, unicode.IsDigit):
+			skipID = false
+			continue
+		case countDigits(w) >= 5:
+			// Terminal and reference numbers; shop numbers are shorter
+			continue
+		}
+		skipID = false
+		words = append(words, w)
+	}
+
+	for trimmed := true; trimmed; {
+		trimmed = false
+		for _, p := range merchantPrefixes {
+			if len(p) < len(words) && slices.Equal(words[:len(p)], p) {
+				words = words[len(p):]
+				trimmed = true
+				break
+			}
+		}
+		// Places only follow the name, so a name like "OSH MARKET" is kept
+		for _, p := range merchantPlaces {
+			if hasSuffixPhrase(words, p) {
+				words = words[:len(words)-len(p)]
+				trimmed = true
+				break
+			}
+		}
+		for _, p := range merchantCities {
+			if hasSuffixPhrase(words, p) {
+				words = words[:len(words)-len(p)]
+				if len(words) > 1 && words[len(words)-1] == "G" {
+					words = words[:len(words)-1]
+				}
+				trimmed = true
+				break
+			}
+		}
+	}
+
+	return strings.Join(words, " ")
+}
+
+// MerchantSimilarity compares two normalized merchant names from 0, when
+// they have nothing in common or either is unknown, to 1 when they are the
+// same. It compares pairs of adjacent characters, so small differences
+// such as "GLOBUS" and "GLOBUS 12", or two transliterations of one name,
+// still score high.
+func MerchantSimilarity(a, b string) float64 {
+	if a == "" || b == "" {
+		return 0
+	}
+	if a == b {
+		return 1
+	}
+
+	ba, bb := bigrams(a), bigrams(b)
+	if len(ba) == 0 || len(bb) == 0 {
+		return 0
+	}
+	counts := make(map[string]int, len(ba))
+	for _, g := range ba {
+		counts[g]++
+	}
+	shared := 0
+	for _, g := range bb {
+		if counts[g] > 0 {
+			counts[g]--
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(ba)+len(bb))
+}
+
+// sameMerchantSimilarity is the MerchantSimilarity from which two merchants
+// are taken to be the same.
+const sameMerchantSimilarity = 0.7
+
+// merchant returns the merchant of t, normalizing the description of
+// transactions that were built without one.
+func (t Transaction) merchant() string {
+	if t.Merchant != "" {
+		return t.Merchant
+	}
+	return NormalizeMerchant(t.Description)
+}
+
+// merchantSimilarity compares the merchants of two transactions.
+func merchantSimilarity(t1, t2 Transaction) float64 {
+	return MerchantSimilarity(t1.merchant(), t2.merchant())
+}
+
+// bigrams returns the pairs of adjacent characters of s, ignoring spaces.
+func bigrams(s string) []string {
+	runes := []rune(strings.ReplaceAll(s, " ", ""))
+	if len(runes) == 1 {
+		return []string{string(runes)}
+	}
+	grams := make([]string, 0, len(runes))
+	for i := 0; i+1 < len(runes); i++ {
+		grams = append(grams, string(runes[i:i+2]))
+	}
+	return grams
+}
+
+// hasSuffixPhrase reports whether words end with phrase and have more words
+// before it.
+func hasSuffixPhrase(words, phrase []string) bool {
+	return len(phrase) < len(words) && slices.Equal(words[len(words)-len(phrase):], phrase)
+}
+
+func countDigits(s string) int {
+	n := 0
+	for _, r := range s {
+		if unicode.IsDigit(r) {
+			n++
+		}
+	}
+	return n
+}
+
+func splitPhrases(phrases ...string) [][]string {
+	result := make([][]string, len(phrases))
+	for i, p := range phrases {
+		result[i] = strings.Fields(p)
+	}
+	return result
+}
+
+// cyrillicToLatin transliterates upper case Russian and Kyrgyz letters the
+// way bank terminals usually spell names.
+var cyrillicToLatin = map[rune]string{
+	'А': "A", 'Б': "B", 'В': "V", 'Г': "G", 'Д': "D", 'Е': "E", 'Ё': "E",
+	'Ж': "ZH", 'З': "Z", 'И': "I", 'Й': "Y", 'К': "K", 'Л': "L", 'М': "M",
+	'Н': "N", 'О': "O", 'П': "P", 'Р': "R", 'С': "S", 'Т': "T", 'У': "U",
+	'Ф': "F", 'Х': "KH", 'Ц': "TS", 'Ч': "CH", 'Ш': "SH", 'Щ': "SHCH",
+	'Ъ': "", 'Ы': "Y", 'Ь': "", 'Э': "E", 'Ю': "YU", 'Я': "YA",
+	'Ң': "NG", 'Ө': "O", 'Ү': "U",
+}
+
+func transliterate(s string) string {
+	var buf strings.Builder
+	for _, r := range s {
+		if latin, ok := cyrillicToLatin[r]; ok {
+			buf.WriteString(latin)
+		} else {
+			buf.WriteRune(r)
+		}
+	}
+	return buf.String()
+}
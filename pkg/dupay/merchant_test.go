@@ -0,0 +1,91 @@
+package dupay
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNormalizeMerchant(t *testing.T) {
+	tests := []struct {
+		description string
+		expected    string
+	}{
+		{"GLOBUS", "GLOBUS"},
+		{"Payment to Globus", "GLOBUS"},
+		{"Оплата товаров и услуг: ГЛОБУС Бишкек KG", "GLOBUS"},
+		{"Покупка в Глобус 12", "GLOBUS 12"},
+		{"Retail GLOBUS BISHKEK KGZ", "GLOBUS"},
+		{"Оплата услуг Beeline", "BEELINE"},
+		{"NETFLIX.COM", "NETFLIX"},
+		{"Purchase NAMBA FOOD 4169585*****1234", "NAMBA FOOD"},
+		{"Card **1234 FRUNZE", "FRUNZE"},
+		{"SHORO 4169XXXX1234 TID: 10012345", "SHORO"},
+		{"Терминал 123 Фрунзе RRN 512345678901", "FRUNZE"},
+		{"EXXON", "EXXON"},
+		{"Чайхана Navat, г. Ош", "CHAYKHANA NAVAT"},
+		{"Globus g. Bishkek KG", "GLOBUS"},
+		// Place names and "г." within the merchant name are kept
+		{"G-STAR BISHKEK", "G STAR"},
+		{"OSH MARKET", "OSH MARKET"},
+		{"KANT SUPERMARKET", "KANT SUPERMARKET"},
+		{"NET HOUSE", "NET HOUSE"},
+		{"KG MOBILE", "KG MOBILE"},
+		{"Shop G", "SHOP G"},
+		{"Перевод РАСУЛОВ ЭМИРЛАН", "PEREVOD RASULOV EMIRLAN"},
+		// A prefix or place alone is kept rather than emptied
+		{"Payment", "PAYMENT"},
+		{"Bishkek", "BISHKEK"},
+		{"", ""},
+		{"****1234", ""},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeMerchant(tt.description); got != tt.expected {
+			t.Errorf("NormalizeMerchant(%q): expected %q, got %q", tt.description, tt.expected, got)
+		}
+	}
+}
+
+func TestMerchantSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected float64
+	}{
+		{"GLOBUS", "GLOBUS", 1},
+		{"GLOBUS", "GLOBUS 12", 10.0 / 12},
+		{"NAMBA FOOD", "NAMBAFOOD", 1},
+		{"GLOBUS", "NAMBA", 0},
+		{"GLOBUS", "", 0},
+		{"", "", 0},
+	}
+
+	for _, tt := range tests {
+		if got := MerchantSimilarity(tt.a, tt.b); got != tt.expected {
+			t.Errorf("MerchantSimilarity(%q, %q): expected %v, got %v", tt.a, tt.b, tt.expected, got)
+		}
+	}
+}
+
+func TestFindDuplicatesSameMerchant(t *testing.T) {
+	baseTime := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+	transactions := []Transaction{
+		{Bank: "Optima Bank", DateTime: baseTime, Amount: NewMoney(-10000, "KGS"), Description: "Оплата товаров и услуг: ГЛОБУС Бишкек"},
+		{Bank: "Mbank", DateTime: baseTime.Add(10 * time.Second), Amount: NewMoney(-10000, "KGS"), Description: "Payment to Globus"},
+		{Bank: "Demir", DateTime: baseTime.Add(20 * time.Second), Amount: NewMoney(-10000, "KGS"), Description: "NAMBA FOOD"},
+		{Bank: "Keremet", DateTime: baseTime.Add(30 * time.Second), Amount: NewMoney(-10000, "KGS")},
+	}
+
+	opts := Options{MaxTimeDiff: time.Minute, MaxAmountDiff: 100}
+	if matches := findDuplicates(transactions, opts); len(matches) != 6 {
+		t.Fatalf("expected every pair without SameMerchant, got %d", len(matches))
+	}
+
+	opts.SameMerchant = true
+	matches := findDuplicates(transactions, opts)
+	if len(matches) != 1 {
+		t.Fatalf("expected only the GLOBUS pair, got %d", len(matches))
+	}
+	if m := matches[0]; m.Transaction1.Bank != "Optima Bank" || m.Transaction2.Bank != "Mbank" {
+		t.Errorf("expected the Optima and Mbank GLOBUS payments, got %s and %s", m.Transaction1.Bank, m.Transaction2.Bank)
+	}
+}
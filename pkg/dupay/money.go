@@ -0,0 +1,161 @@
+package dupay
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// minorPerMajor is the number of minor units (tyiyn, cents) in one unit of
+// currency. All currencies found on supported statements use two decimals.
+const minorPerMajor = 100
+
+// currencyCodes are the ISO codes recognized when parsing statements.
+var currencyCodes = map[string]bool{
+	"KGS": true,
+	"USD": true,
+	"EUR": true,
+	"RUB": true,
+	"KZT": true,
+	"CNY": true,
+	"GBP": true,
+	"TRY": true,
+}
+
+// isCurrencyCode reports whether s is a recognized ISO currency code.
+func isCurrencyCode(s string) bool {
+	return currencyCodes[s]
+}
+
+// Money is an exact monetary amount stored as an integer number of minor
+// units, together with its ISO currency code.
+type Money struct {
+	// Minor is the amount in minor units, e.g. -101800 for -1018.00.
+	Minor int64
+	// Currency is the ISO currency code (e.g., "KGS", "USD").
+	Currency string
+}
+
+// NewMoney creates a Money value from minor units and a currency code.
+func NewMoney(minor int64, currency string) Money {
+	return Money{Minor: minor, Currency: currency}
+}
+
+// ParseMoney parses a decimal amount such as "-1018.00" in the given currency.
+func ParseMoney(s, currency string) (Money, error) {
+	minor, err := ParseAmount(s)
+	if err != nil {
+		return Money{}, err
+	}
+	return NewMoney(minor, currency), nil
+}
+
+// ParseAmount parses a plain decimal string with a dot separator and at most
+// two decimal places (e.g. "-1018", "1018.5", "+1018.00") into minor units.
+func ParseAmount(s string) (int64, error) {
+	orig := s
+	s = strings.TrimSpace(s)
+
+	negative := false
+	if rest, ok := strings.CutPrefix(s, "-"); ok {
+		negative = true
+		s = rest
+	} else if rest, ok := strings.CutPrefix(s, "+"); ok {
+		s = rest
+	}
+
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" && frac == "" {
+		return 0, fmt.Errorf("invalid amount %q", orig)
+	}
+	if len(frac) > 2 {
+		return 0, fmt.Errorf("invalid amount %q: more than two decimal places", orig)
+	}
+	for _, r := range whole + frac {
+		if r < '0' || r > '9' {
+			return 0, fmt.Errorf("invalid amount %q", orig)
+		}
+	}
+
+	digits := whole + frac + strings.Repeat("0", 2-len(frac))
+	minor, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q: %w", orig, err)
+	}
+	if negative {
+		minor = -minor
+	}
+	return minor, nil
+}
+
+// FormatAmount formats minor units as a decimal string with two decimal
+// places, e.g. -101800 becomes "-1018.00".
+func FormatAmount(minor int64) string {
+	sign := ""
+	if minor < 0 {
+		sign = "-"
+	}
+	abs := absMinor(minor)
+	return fmt.Sprintf("%s%d.%02d", sign, abs/minorPerMajor, abs%minorPerMajor)
+}
+
+// Decimal returns the amount without the currency, e.g. "-1018.00".
+func (m Money) Decimal() string {
+	return FormatAmount(m.Minor)
+}
+
+// String returns the amount followed by its currency, e.g. "-1018.00 KGS".
+func (m Money) String() string {
+	if m.Currency == "" {
+		return m.Decimal()
+	}
+	return m.Decimal() + " " + m.Currency
+}
+
+// IsZero reports whether the amount is zero.
+func (m Money) IsZero() bool {
+	return m.Minor == 0
+}
+
+// IsNegative reports whether the amount is a debit.
+func (m Money) IsNegative() bool {
+	return m.Minor < 0
+}
+
+// Abs returns the absolute value of the amount.
+func (m Money) Abs() Money {
+	return NewMoney(absMinor(m.Minor), m.Currency)
+}
+
+// Neg returns the amount with the opposite sign.
+func (m Money) Neg() Money {
+	return NewMoney(-m.Minor, m.Currency)
+}
+
+// Add returns m + o. Both values are expected to share a currency; the
+// result keeps the currency of m.
+func (m Money) Add(o Money) Money {
+	return NewMoney(m.Minor+o.Minor, m.Currency)
+}
+
+// Sub returns m - o. Both values are expected to share a currency; the
+// result keeps the currency of m.
+func (m Money) Sub(o Money) Money {
+	return NewMoney(m.Minor-o.Minor, m.Currency)
+}
+
+func absMinor(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// scaleMinor returns v*num/den, rounding halves away from zero.
+func scaleMinor(v, num, den int64) int64 {
+	p := v * num
+	if p < 0 {
+		return -((-p + den/2) / den)
+	}
+	return (p + den/2) / den
+}
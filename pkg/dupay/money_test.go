@@ -0,0 +1,115 @@
+package dupay
+
+import (
+	"testing"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected int64
+		wantErr  bool
+	}{
+		{name: "whole number", input: "1018", expected: 101800},
+		{name: "two decimals", input: "1018.00", expected: 101800},
+		{name: "one decimal", input: "1018.5", expected: 101850},
+		{name: "negative", input: "-1965.84", expected: -196584},
+		{name: "explicit plus", input: "+5000.00", expected: 500000},
+		{name: "fraction only", input: ".5", expected: 50},
+		{name: "surrounding spaces", input: " 12.34 ", expected: 1234},
+		{name: "no float rounding", input: "0.29", expected: 29},
+		{name: "empty", input: "", wantErr: true},
+		{name: "sign only", input: "-", wantErr: true},
+		{name: "three decimals", input: "1.005", wantErr: true},
+		{name: "letters", input: "12a.00", wantErr: true},
+		{name: "inner space", input: "1 000.00", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseAmount(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %d", result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("expected %d, got %d", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestFormatAmount(t *testing.T) {
+	tests := []struct {
+		input    int64
+		expected string
+	}{
+		{input: 0, expected: "0.00"},
+		{input: 5, expected: "0.05"},
+		{input: -5, expected: "-0.05"},
+		{input: 101800, expected: "1018.00"},
+		{input: -196584, expected: "-1965.84"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			if result := FormatAmount(tt.input); result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	a := NewMoney(-10000, "KGS")
+	b := NewMoney(-10050, "KGS")
+
+	if got := a.Abs().Sub(b.Abs()).Abs(); got != NewMoney(50, "KGS") {
+		t.Errorf("expected 0.50 KGS difference, got %v", got)
+	}
+	if got := a.Add(b); got != NewMoney(-20050, "KGS") {
+		t.Errorf("expected -200.50 KGS sum, got %v", got)
+	}
+	if !a.IsNegative() || a.Neg().IsNegative() {
+		t.Error("unexpected sign handling")
+	}
+	if got := a.String(); got != "-100.00 KGS" {
+		t.Errorf("expected %q, got %q", "-100.00 KGS", got)
+	}
+
+	// Summing many small amounts must stay exact
+	var total Money
+	for i := 0; i < 1000; i++ {
+		total = total.Add(NewMoney(10, "KGS"))
+	}
+	if total.Minor != 10000 {
+		t.Errorf("expected exact total of 100.00, got %s", total.Decimal())
+	}
+}
+
+func TestScaleMinor(t *testing.T) {
+	tests := []struct {
+		v, num, den int64
+		expected    int64
+	}{
+		{v: 4, num: 1, den: 2, expected: 2},
+		{v: 5, num: 1, den: 2, expected: 3},
+		{v: -5, num: 1, den: 2, expected: -3},
+		{v: -20050, num: 1, den: 2, expected: -10025},
+		{v: -30000, num: 2, den: 3, expected: -20000},
+		{v: -10001, num: 2, den: 3, expected: -6667},
+		{v: 100, num: 0, den: 1, expected: 0},
+	}
+
+	for _, tt := range tests {
+		if result := scaleMinor(tt.v, tt.num, tt.den); result != tt.expected {
+			t.Errorf("scaleMinor(%d, %d, %d): expected %d, got %d", tt.v, tt.num, tt.den, tt.expected, result)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package dupay
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseResult is everything a parser extracted from a statement.
+type ParseResult struct {
+	// Transactions holds the parsed transactions, in statement order.
+	Transactions []Transaction
+	// Warnings describes text that looked like a transaction but could not
+	// be parsed.
+	Warnings []ParseWarning
+	// Lines counts how the non-empty lines of the content were used.
+	Lines LineStats
+	// Statement describes the account and period, for parsers that extract
+	// them, including the declared totals checked by CheckTotals.
+	Statement Statement
+}
+
+// ParseWarning reports a transaction a parser had to drop.
+type ParseWarning struct {
+	// Source holds the lines of the dropped text.
+	Source Source
+	// Text is the first line of the dropped text.
+	Text string
+	// Reason explains why the text was dropped.
+	Reason string
+}
+
+func (w ParseWarning) String() string {
+	return fmt.Sprintf("%s: %s: %q", w.Source, w.Reason, w.Text)
+}
+
+// LineStats counts the non-empty lines of a statement by how the parser
+// used them. A sudden rise in skipped or rejected lines usually means the
+// bank changed its statement layout.
+type LineStats struct {
+	// Consumed lines are part of a parsed transaction.
+	Consumed int
+	// Skipped lines are headers, footers and other text the parser ignores.
+	Skipped int
+	// Rejected lines are part of a transaction that was dropped with a warning.
+	Rejected int
+}
+
+// Total returns the number of non-empty lines.
+func (s LineStats) Total() int {
+	return s.Consumed + s.Skipped + s.Rejected
+}
+
+type lineState uint8
+
+const (
+	lineSkipped lineState = iota
+	lineConsumed
+	lineRejected
+)
+
+// lineTracker records which lines a parser consumed or rejected, so every
+// parser reports its diagnostics the same way. Line numbers are 1-based.
+type lineTracker struct {
+	lines    []string
+	state    []lineState
+	warnings []ParseWarning
+}
+
+func newLineTracker(lines []string) *lineTracker {
+	return &lineTracker{lines: lines, state: make([]lineState, len(lines))}
+}
+
+// consume marks lines start to end as part of a parsed transaction.
+func (t *lineTracker) consume(start, end int) {
+	t.mark(start, end, lineConsumed)
+}
+
+// reject marks lines start to end as a dropped transaction and records a
+// warning with the given reason.
+func (t *lineTracker) reject(start, end int, reason string) {
+	t.mark(start, end, lineRejected)
+	var text string
+	if start >= 1 && start <= len(t.lines) {
+		text = strings.TrimSpace(t.lines[start-1])
+	}
+	t.warnings = append(t.warnings, ParseWarning{
+		Source: Source{StartLine: start, EndLine: end},
+		Text:   text,
+		Reason: reason,
+	})
+}
+
+func (t *lineTracker) mark(start, end int, state lineState) {
+	for line := max(start, 1); line <= end && line <= len(t.state); line++ {
+		t.state[line-1] = state
+	}
+}
+
+// result builds the ParseResult for the given transactions.
+func (t *lineTracker) result(transactions []Transaction) ParseResult {
+	var stats LineStats
+	for i, line := range t.lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		switch t.state[i] {
+		case lineConsumed:
+			stats.Consumed++
+		case lineRejected:
+			stats.Rejected++
+		default:
+			stats.Skipped++
+		}
+	}
+	return ParseResult{Transactions: transactions, Warnings: t.warnings, Lines: stats}
+}
+
+// lastNonEmptyLine returns the 1-based number of the last non-empty line
+// before index end (exclusive), but not before start.
+func lastNonEmptyLine(lines []string, start, end int) int {
+	for end > start && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
+	return end
+}
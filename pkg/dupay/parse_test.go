@@ -0,0 +1,68 @@
+package dupay
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLineTracker(t *testing.T) {
+	lines := []string{"Header", "", "10:00 Payment", "-500.00", "Broken row", "Footer"}
+
+	tracker := newLineTracker(lines)
+	tracker.consume(3, 4)
+	tracker.reject(5, 5, "no amount")
+	result := tracker.result(nil)
+
+	expectedStats := LineStats{Consumed: 2, Skipped: 2, Rejected: 1}
+	if result.Lines != expectedStats {
+		t.Errorf("expected %+v, got %+v", expectedStats, result.Lines)
+	}
+	if result.Lines.Total() != 5 {
+		t.Errorf("expected 5 non-empty lines, got %d", result.Lines.Total())
+	}
+
+	expectedWarnings := []ParseWarning{
+		{Source: Source{StartLine: 5, EndLine: 5}, Text: "Broken row", Reason: "no amount"},
+	}
+	if !reflect.DeepEqual(result.Warnings, expectedWarnings) {
+		t.Errorf("expected %+v, got %+v", expectedWarnings, result.Warnings)
+	}
+}
+
+func TestParseWarningString(t *testing.T) {
+	w := ParseWarning{
+		Source: Source{File: "/tmp/mbank.pdf", Page: 2, StartLine: 14, EndLine: 15},
+		Text:   "24.12.2025 12:02 Payment",
+		Reason: "zero amount",
+	}
+	expected := `mbank.pdf, page 2, lines 14-15: zero amount: "24.12.2025 12:02 Payment"`
+	if w.String() != expected {
+		t.Errorf("expected %q, got %q", expected, w.String())
+	}
+}
+
+func TestDocumentAttachResult(t *testing.T) {
+	doc := &Document{
+		Pages: []Page{
+			{Number: 1, Text: "Mbank Statement\n24.12.2025 10:00 Payment - 500,00"},
+			{Number: 2, Text: "24.12.2025 11:00 Broken row"},
+		},
+	}
+
+	result, err := NewMbankParser().Parse(doc.Text())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	doc.AttachResult("mbank.pdf", &result)
+
+	if len(result.Warnings) != 1 {
+		t.Fatalf("expected 1 warning, got %d", len(result.Warnings))
+	}
+	expected := Source{File: "mbank.pdf", Page: 2, StartLine: 1, EndLine: 1}
+	if result.Warnings[0].Source != expected {
+		t.Errorf("expected %+v, got %+v", expected, result.Warnings[0].Source)
+	}
+	if result.Transactions[0].Source.File != "mbank.pdf" {
+		t.Errorf("expected transaction source to be attached, got %+v", result.Transactions[0].Source)
+	}
+}
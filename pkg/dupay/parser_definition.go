@@ -0,0 +1,439 @@
+package dupay
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ParserDefinition describes a bank's statement format, so a bank can be
+// supported by writing a JSON file instead of a parser in Go. See
+// NewDefinitionParser for how a definition is applied.
+type ParserDefinition struct {
+	// ID is the short lowercase identifier used to select the parser by hand.
+	ID string `json:"id"`
+	// Bank is the human-readable name of the bank.
+	Bank string `json:"bank"`
+	// Currency is the account currency, used when there is no currency column.
+	Currency string `json:"currency"`
+	// Markers are the weighted text fragments that identify the statements.
+	Markers []Marker `json:"markers"`
+	// Skip lists the beginnings of header and footer lines to ignore.
+	Skip []string `json:"skip"`
+	// SkipPatterns lists regular expressions for other lines to ignore.
+	SkipPatterns []string `json:"skip_patterns"`
+	// Columns lists the fields of an operation in the order the statement
+	// prints them: "date", "time", "description", "amount", "fee",
+	// "currency", or any other name for a column that is read and ignored,
+	// such as a running balance.
+	Columns []string `json:"columns"`
+	// Fields overrides how the columns are matched and read.
+	Fields map[string]FieldDefinition `json:"fields"`
+	// Number describes how amounts are written.
+	Number NumberFormat `json:"number"`
+	// Account is a regular expression whose first group is the account
+	// number, searched for in the whole statement.
+	Account string `json:"account"`
+}
+
+// FieldDefinition describes how one column is matched and read.
+type FieldDefinition struct {
+	// Pattern is the regular expression the column matches.
+	Pattern string `json:"pattern"`
+	// Format is the Go time layout of a date or time column, such as
+	// "02.01.2006" or "15:04".
+	Format string `json:"format"`
+}
+
+// NumberFormat describes how amounts are written, e.g. "- 1 018,00" has the
+// decimal separator "," and the thousands separator " ". Spaces are always
+// ignored, including between the sign and the digits.
+type NumberFormat struct {
+	// Decimal is the decimal separator, "." by default.
+	Decimal string `json:"decimal"`
+	// Thousands is the separator between groups of digits, if any.
+	Thousands string `json:"thousands"`
+}
+
+// defaultFields are the patterns and formats used for columns a definition
+// doesn't describe in Fields. The amount pattern is built from the number
+// format.
+var defaultFields = map[string]FieldDefinition{
+	"date":        {Pattern: `\d{2}\.\d{2}\.\d{4}`, Format: "02.01.2006"},
+	"time":        {Pattern: `\d{2}:\d{2}`, Format: "15:04"},
+	"description": {Pattern: `.*?`},
+	"currency":    {Pattern: `[A-Z]{3}`},
+}
+
+// DefinitionParser parses statements as described by a ParserDefinition.
+type DefinitionParser struct {
+	def ParserDefinition
+	// start matches the first line of an operation: the columns before the
+	// description.
+	start *regexp.Regexp
+	// operation matches the text of a whole operation, with a group per column.
+	operation    *regexp.Regexp
+	skipPatterns []*regexp.Regexp
+	account      *regexp.Regexp
+	dateFormat   string
+	timeFormat   string
+}
+
+// NewDefinitionParser checks a definition and compiles its patterns.
+//
+// The parser reads the statement line by line, ignoring lines that start
+// with one of Skip or match one of SkipPatterns. An operation starts at a line
+// that begins with the columns before the description, typically its date,
+// and continues on the following lines until its text matches all Columns,
+// joined by optional spaces. Amounts are negative for debits.
+func NewDefinitionParser(def ParserDefinition) (*DefinitionParser, error) {
+	switch {
+	case def.ID == "" || def.ID != strings.ToLower(def.ID) || strings.ContainsFunc(def.ID, unicode.IsSpace):
+		return nil, fmt.Errorf("id %q must be a lowercase word", def.ID)
+	case def.Bank == "":
+		return nil, errors.New("missing bank name")
+	case len(def.Markers) == 0:
+		return nil, errors.New("missing detection markers")
+	case !slices.Contains(def.Columns, "date"):
+		return nil, errors.New(`columns must include "date"`)
+	case !slices.Contains(def.Columns, "amount"):
+		return nil, errors.New(`columns must include "amount"`)
+	case def.Columns[0] == "description":
+		return nil, errors.New("the first column must not be the description, so operations can be told apart")
+	case def.Currency == "" && !slices.Contains(def.Columns, "currency"):
+		return nil, errors.New(`missing currency, and columns don't include "currency"`)
+	case def.Currency != "" && !isCurrencyCode(def.Currency):
+		return nil, fmt.Errorf("invalid currency %q", def.Currency)
+	case def.Number.Thousands != "" && def.Number.Thousands == cmp.Or(def.Number.Decimal, "."):
+		return nil, fmt.Errorf("the decimal and thousands separators are both %q", def.Number.Thousands)
+	}
+	for _, m := range def.Markers {
+		if m.Text == "" || m.Weight == 0 {
+			return nil, fmt.Errorf("marker %q needs a text and a weight", m.Text)
+		}
+	}
+
+	p := &DefinitionParser{def: def}
+	if p.def.Number.Decimal == "" {
+		p.def.Number.Decimal = "."
+	}
+
+	var start, operation []string
+	seen := make(map[string]bool)
+	for i, name := range def.Columns {
+		if seen[name] {
+			return nil, fmt.Errorf("column %q is listed twice", name)
+		}
+		seen[name] = true
+		if !isFieldName(name) {
+			return nil, fmt.Errorf("invalid column name %q", name)
+		}
+
+		field, err := p.field(name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := regexp.Compile(field.Pattern); err != nil {
+			return nil, fmt.Errorf("column %q: %w", name, err)
+		}
+		group := fmt.Sprintf("(?P<%s>%s)", name, field.Pattern)
+		operation = append(operation, group)
+		if !slices.Contains(def.Columns[:i+1], "description") {
+			start = append(start, group)
+		}
+
+		switch name {
+		case "date":
+			p.dateFormat = field.Format
+		case "time":
+			p.timeFormat = field.Format
+		}
+	}
+	if p.dateFormat == "" || (seen["time"] && p.timeFormat == "") {
+		return nil, errors.New("date and time columns need a format")
+	}
+	for name := range def.Fields {
+		if !seen[name] {
+			return nil, fmt.Errorf("field %q is not one of the columns", name)
+		}
+	}
+
+	var err error
+	if p.start, err = regexp.Compile(`^` + strings.Join(start, `\s*`)); err != nil {
+		return nil, err
+	}
+	if p.operation, err = regexp.Compile(`^` + strings.Join(operation, `\s*`) + `$`); err != nil {
+		return nil, err
+	}
+	for _, pattern := range def.SkipPatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("skip pattern: %w", err)
+		}
+		p.skipPatterns = append(p.skipPatterns, re)
+	}
+	if def.Account != "" {
+		if p.account, err = regexp.Compile(def.Account); err != nil {
+			return nil, fmt.Errorf("account: %w", err)
+		}
+		if p.account.NumSubexp() < 1 {
+			return nil, errors.New("account pattern needs a group for the account number")
+		}
+	}
+	return p, nil
+}
+
+// field returns how the named column is matched and read, filling in the
+// defaults.
+func (p *DefinitionParser) field(name string) (FieldDefinition, error) {
+	field := p.def.Fields[name]
+	def := defaultFields[name]
+	if field.Pattern == "" {
+		field.Pattern = def.Pattern
+	}
+	if field.Format == "" {
+		field.Format = def.Format
+	}
+	if field.Pattern == "" && (name == "amount" || name == "fee") {
+		field.Pattern = p.amountPattern()
+	}
+	if field.Pattern == "" {
+		return field, fmt.Errorf("column %q is not a known field and needs a pattern", name)
+	}
+	return field, nil
+}
+
+// amountPattern matches amounts in the definition's number format, such as
+// "-1 018,00" for the decimal separator "," and the thousands separator " ".
+func (p *DefinitionParser) amountPattern() string {
+	digits := `\d`
+	if t := p.def.Number.Thousands; t != "" {
+		digits = `[\d` + regexp.QuoteMeta(t) + `]`
+	}
+	return `[-+]?\s*\d(?:` + digits + `*\d)?` + regexp.QuoteMeta(p.def.Number.Decimal) + `\d{1,2}`
+}
+
+// BankName returns the human-readable name of the bank.
+func (p *DefinitionParser) BankName() string {
+	return p.def.Bank
+}
+
+// ID returns the identifier used to select this parser by hand.
+func (p *DefinitionParser) ID() string {
+	return p.def.ID
+}
+
+// Score rates how much the content looks like a statement of the bank.
+func (p *DefinitionParser) Score(content string) int {
+	return scoreMarkers(content, p.def.Markers)
+}
+
+// Markers returns the weighted text fragments that identify the statements.
+func (p *DefinitionParser) Markers() []Marker {
+	return slices.Clone(p.def.Markers)
+}
+
+// Parse extracts transactions from statement text as the definition describes.
+func (p *DefinitionParser) Parse(content string) (ParseResult, error) {
+	var transactions []Transaction
+
+	lines := strings.Split(content, "\n")
+	tracker := newLineTracker(lines)
+
+	statement := Statement{Bank: p.BankName(), Currency: p.def.Currency}
+	if p.account != nil {
+		if m := p.account.FindStringSubmatch(content); m != nil {
+			statement.Account = MaskAccount(m[1])
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" || p.skipped(line) || !p.start.MatchString(line) {
+			continue
+		}
+
+		// Add continuation lines until the operation has all its columns
+		text := line
+		startLine := i + 1
+		m := p.operation.FindStringSubmatch(text)
+		for j := i + 1; m == nil && j < len(lines); j++ {
+			next := strings.TrimSpace(lines[j])
+			if next == "" || p.skipped(next) {
+				continue
+			}
+			if p.start.MatchString(next) {
+				break
+			}
+			text += " " + next
+			i = j
+			m = p.operation.FindStringSubmatch(text)
+		}
+		endLine := i + 1
+
+		if m == nil {
+			tracker.reject(startLine, endLine, "operation does not match the columns")
+			continue
+		}
+		t, err := p.transaction(m)
+		if err != nil {
+			tracker.reject(startLine, endLine, err.Error())
+			continue
+		}
+
+		t.Account = statement.Account
+		t.RawLine = line
+		t.Source = Source{StartLine: startLine, EndLine: endLine}
+		tracker.consume(startLine, endLine)
+		transactions = append(transactions, t)
+	}
+
+	result := tracker.result(transactions)
+	result.Statement = statement
+	return result, nil
+}
+
+// skipped reports whether line is a header or footer line to ignore.
+func (p *DefinitionParser) skipped(line string) bool {
+	for _, prefix := range p.def.Skip {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+	for _, re := range p.skipPatterns {
+		if re.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// transaction builds a transaction from the columns of a matched operation.
+func (p *DefinitionParser) transaction(m []string) (Transaction, error) {
+	column := func(name string) string {
+		if i := p.operation.SubexpIndex(name); i > 0 {
+			return strings.TrimSpace(m[i])
+		}
+		return ""
+	}
+
+	layout, value := p.dateFormat, column("date")
+	if p.timeFormat != "" {
+		layout, value = layout+" "+p.timeFormat, value+" "+column("time")
+	}
+	dateTime, err := time.Parse(layout, value)
+	if err != nil {
+		return Transaction{}, fmt.Errorf("invalid date %q", value)
+	}
+
+	currency := p.def.Currency
+	if c := column("currency"); c != "" {
+		currency = c
+	}
+	amount, err := p.parseAmount(column("amount"))
+	if err != nil {
+		return Transaction{}, fmt.Errorf("invalid amount %q", column("amount"))
+	}
+	if amount == 0 {
+		return Transaction{}, errors.New("zero amount")
+	}
+
+	// A zero fee is left unset, as the built-in parsers do
+	var fee Money
+	if s := column("fee"); s != "" {
+		minor, err := p.parseAmount(s)
+		if err != nil {
+			return Transaction{}, fmt.Errorf("invalid fee %q", s)
+		}
+		if minor != 0 {
+			fee = NewMoney(absMinor(minor), currency)
+		}
+	}
+
+	description := column("description")
+	return Transaction{
+		DateTime:    dateTime,
+		Description: description,
+		Merchant:    NormalizeMerchant(description),
+		Amount:      NewMoney(amount, currency),
+		Fee:         fee,
+		Bank:        p.BankName(),
+	}, nil
+}
+
+// parseAmount parses an amount in the definition's number format into minor
+// units.
+func (p *DefinitionParser) parseAmount(s string) (int64, error) {
+	s = strings.Join(strings.Fields(s), "")
+	if t := p.def.Number.Thousands; strings.TrimSpace(t) != "" {
+		s = strings.ReplaceAll(s, t, "")
+	}
+	return ParseAmount(strings.ReplaceAll(s, p.def.Number.Decimal, "."))
+}
+
+// isFieldName reports whether name can be used as a regular expression
+// group name.
+func isFieldName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		if !(r == '_' || r >= 'a' && r <= 'z' || i > 0 && r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// ReadParserDefinition reads a parser definition from a JSON file.
+// Unknown fields are rejected, so a misspelled setting is not silently
+// ignored.
+func ReadParserDefinition(path string) (*DefinitionParser, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var def ParserDefinition
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&def); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	p, err := NewDefinitionParser(def)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return p, nil
+}
+
+// LoadParserDefinitions reads every .json parser definition in dir, in file
+// name order.
+func LoadParserDefinitions(dir string) ([]BankParser, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, err
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var parsers []BankParser
+	for _, path := range paths {
+		p, err := ReadParserDefinition(path)
+		if err != nil {
+			return nil, err
+		}
+		parsers = append(parsers, p)
+	}
+	return parsers, nil
+}
@@ -0,0 +1,204 @@
+package dupay
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadParserDefinitions(t *testing.T) {
+	parsers, err := LoadParserDefinitions(filepath.Join("testdata", "parsers"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(parsers) != 1 {
+		t.Fatalf("expected 1 parser, got %d", len(parsers))
+	}
+
+	p := parsers[0]
+	if p.ID() != "demir" || p.BankName() != "Demir Bank" {
+		t.Errorf("expected demir / Demir Bank, got %s / %s", p.ID(), p.BankName())
+	}
+	if score := p.Score("DemirBank www.demirbank.kg"); score != MaxScore {
+		t.Errorf("expected score %d, got %d", MaxScore, score)
+	}
+	if score := p.Score("Mbank statement"); score != 0 {
+		t.Errorf("expected score 0 for another bank, got %d", score)
+	}
+
+	if _, err := LoadParserDefinitions(filepath.Join("testdata", "missing")); err == nil {
+		t.Error("expected an error for a missing directory")
+	}
+}
+
+func TestDefinitionParser_Parse(t *testing.T) {
+	parsers, err := LoadParserDefinitions(filepath.Join("testdata", "parsers"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	content := `DemirBank www.demirbank.kg
+Счет № 1180000012345678
+Дата Описание Сумма Комиссия Остаток
+15/01/2025 10:30 Оплата товаров и услуг: ГЛОБУС -1,500.00 0.00 48,500.00
+16/01/2025 09:00 Перевод на карту
+другого банка -2,000.00 20.00 46,480.00
+Страница 1 из 1
+17/01/2025 12:00 Возврат 300.00 0.00 46,780.00
+17/01/2025 13:00 Операция без суммы
+Итого: -3,200.00`
+
+	result, err := parsers[0].Parse(content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	account := "1180********5678"
+	expected := []Transaction{
+		{
+			DateTime:    time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC),
+			Description: "Оплата товаров и услуг: ГЛОБУС",
+			Merchant:    "GLOBUS",
+			Amount:      NewMoney(-150000, "KGS"),
+			Bank:        "Demir Bank",
+			Account:     account,
+			RawLine:     "15/01/2025 10:30 Оплата товаров и услуг: ГЛОБУС -1,500.00 0.00 48,500.00",
+			Source:      Source{StartLine: 4, EndLine: 4},
+		},
+		{
+			DateTime:    time.Date(2025, 1, 16, 9, 0, 0, 0, time.UTC),
+			Description: "Перевод на карту другого банка",
+			Merchant:    "PEREVOD NA KARTU DRUGOGO BANKA",
+			Amount:      NewMoney(-200000, "KGS"),
+			Fee:         NewMoney(2000, "KGS"),
+			Bank:        "Demir Bank",
+			Account:     account,
+			RawLine:     "16/01/2025 09:00 Перевод на карту",
+			Source:      Source{StartLine: 5, EndLine: 6},
+		},
+		{
+			DateTime:    time.Date(2025, 1, 17, 12, 0, 0, 0, time.UTC),
+			Description: "Возврат",
+			Merchant:    "VOZVRAT",
+			Amount:      NewMoney(30000, "KGS"),
+			Bank:        "Demir Bank",
+			Account:     account,
+			RawLine:     "17/01/2025 12:00 Возврат 300.00 0.00 46,780.00",
+			Source:      Source{StartLine: 8, EndLine: 8},
+		},
+	}
+	if !reflect.DeepEqual(result.Transactions, expected) {
+		t.Errorf("expected %+v, got %+v", expected, result.Transactions)
+	}
+
+	if len(result.Warnings) != 1 || result.Warnings[0].Source.StartLine != 9 {
+		t.Errorf("expected a warning for the operation without an amount, got %+v", result.Warnings)
+	}
+	if result.Statement.Account != account || result.Statement.Currency != "KGS" {
+		t.Errorf("unexpected statement: %+v", result.Statement)
+	}
+}
+
+func TestDefinitionParser_MatchesMbankParser(t *testing.T) {
+	p, err := NewDefinitionParser(ParserDefinition{
+		ID:       "mbank",
+		Bank:     "Mbank",
+		Currency: "KGS",
+		Markers:  mbankMarkers,
+		Skip:     []string{"Выписка по счету", "За период", "Всего", "Для проверки"},
+		Columns:  []string{"date", "time", "description", "amount"},
+		Number:   NumberFormat{Decimal: ",", Thousands: " "},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	content := `Mbank Statement
+Выписка по счету
+За период с 01.12.2025 по 31.12.2025
+24.12.2025 10:00 Payment - 500,00
+24.12.2025 11:00 First line of
+
+description continues here - 1 500,00
+24.12.2025 14:30 Пополнение счета 5 000,00
+Всего списаний: 2 000,00`
+
+	got, err := p.Parse(content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected, err := NewMbankParser().Parse(content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got.Transactions) != 3 {
+		t.Fatalf("expected 3 transactions, got %d", len(got.Transactions))
+	}
+	if !reflect.DeepEqual(got.Transactions, expected.Transactions) {
+		t.Errorf("expected the Mbank parser's transactions %+v, got %+v", expected.Transactions, got.Transactions)
+	}
+	if got.Lines != expected.Lines {
+		t.Errorf("expected line stats %+v, got %+v", expected.Lines, got.Lines)
+	}
+}
+
+func TestNewDefinitionParserErrors(t *testing.T) {
+	valid := func() ParserDefinition {
+		return ParserDefinition{
+			ID:       "bank",
+			Bank:     "Bank",
+			Currency: "KGS",
+			Markers:  []Marker{{Text: "Bank", Weight: 50}},
+			Columns:  []string{"date", "description", "amount"},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		change func(*ParserDefinition)
+		errMsg string
+	}{
+		{"uppercase id", func(d *ParserDefinition) { d.ID = "Bank" }, "lowercase"},
+		{"no markers", func(d *ParserDefinition) { d.Markers = nil }, "markers"},
+		{"no amount", func(d *ParserDefinition) { d.Columns = []string{"date", "description"} }, `"amount"`},
+		{"description first", func(d *ParserDefinition) { d.Columns = []string{"description", "date", "amount"} }, "first column"},
+		{"no currency", func(d *ParserDefinition) { d.Currency = "" }, "currency"},
+		{"unknown column", func(d *ParserDefinition) { d.Columns = append(d.Columns, "balance") }, `"balance" is not a known field`},
+		{"repeated column", func(d *ParserDefinition) { d.Columns = append(d.Columns, "date") }, "twice"},
+		{"invalid pattern", func(d *ParserDefinition) { d.Fields = map[string]FieldDefinition{"date": {Pattern: "("}} }, `column "date"`},
+		{"field of no column", func(d *ParserDefinition) { d.Fields = map[string]FieldDefinition{"time": {Pattern: `\d`}} }, `field "time"`},
+		{"account without group", func(d *ParserDefinition) { d.Account = `\d+` }, "group"},
+		{"same separators", func(d *ParserDefinition) { d.Number = NumberFormat{Decimal: ",", Thousands: ","} }, "separators"},
+		{"thousands is the default decimal", func(d *ParserDefinition) { d.Number = NumberFormat{Thousands: "."} }, "separators"},
+	}
+
+	if _, err := NewDefinitionParser(valid()); err != nil {
+		t.Fatalf("unexpected error for a valid definition: %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			def := valid()
+			tt.change(&def)
+			_, err := NewDefinitionParser(def)
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("expected an error containing %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+}
+
+func TestReadParserDefinitionRejectsUnknownFields(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bank.json")
+	data := `{"id": "bank", "bank": "Bank", "currency": "KGS", "markers": [{"text": "Bank", "weight": 50}], "colums": ["date", "amount"]}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := ReadParserDefinition(path)
+	if err == nil || !strings.Contains(err.Error(), "colums") {
+		t.Errorf("expected an error naming the misspelled field, got %v", err)
+	}
+}
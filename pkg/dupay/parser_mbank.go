@@ -0,0 +1,340 @@
+package dupay
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// mbankMarkers are text fragments that identify Mbank statements. A bare
+// "Mbank" is weak evidence, since other banks' statements mention Mbank
+// transfers.
+var mbankMarkers = []Marker{
+	{Text: "mbank.kg", Weight: 60},
+	{Text: "МБАНК", Weight: 60},
+	{Text: "Выписка по счету", Weight: 20},
+	{Text: "Всего списаний", Weight: 20},
+	{Text: "Mbank", Weight: 20},
+}
+
+// MbankParser parses Mbank PDF statements.
+type MbankParser struct{}
+
+// NewMbankParser creates a parser for Mbank statements.
+func NewMbankParser() *MbankParser {
+	return &MbankParser{}
+}
+
+// BankName returns the human-readable name of the bank.
+func (p *MbankParser) BankName() string {
+	return "Mbank"
+}
+
+// ID returns the identifier used to select this parser by hand.
+func (p *MbankParser) ID() string {
+	return "mbank"
+}
+
+// Score rates how much the content looks like an Mbank statement.
+func (p *MbankParser) Score(content string) int {
+	return scoreMarkers(content, mbankMarkers)
+}
+
+// Markers returns the weighted text fragments that identify an Mbank statement.
+func (p *MbankParser) Markers() []Marker {
+	return slices.Clone(mbankMarkers)
+}
+
+// Parse extracts transactions from Mbank statement text.
+func (p *MbankParser) Parse(content string) (ParseResult, error) {
+	var transactions []Transaction
+
+	lines := strings.Split(content, "\n")
+	tracker := newLineTracker(lines)
+
+	// The header tells whose statement this is; the holder's name is repeated
+	// in the header of every page and must not end up in descriptions
+	statement := parseMbankStatement(lines)
+	statement.Bank = p.BankName()
+
+	// Regex patterns
+	// Match date-time at start of line like "24.12.2025 12:02"
+	dateTimePattern := regexp.MustCompile(`^(\d{2}\.\d{2}\.\d{4})\s+(\d{2}:\d{2})`)
+	// Match amount like "- 1 018,00" or "-1018,00" or "1 018,00"
+	amountPattern := regexp.MustCompile(`(-?\s*[\d\s]+,\d{2})$`)
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+
+		// Skip headers and footers
+		if line == "" ||
+			strings.HasPrefix(line, "Выписка по счету") ||
+			strings.HasPrefix(line, "За период") ||
+			strings.HasPrefix(line, "Дата формирования") ||
+			strings.HasPrefix(line, "Клиент") ||
+			strings.HasPrefix(line, "Баланс") ||
+			line == "Дата операции" ||
+			line == "Описание операции" ||
+			line == "Сумма операции" ||
+			strings.HasPrefix(line, "Всего списаний") ||
+			strings.HasPrefix(line, "Всего пополнений") ||
+			strings.HasPrefix(line, "Для проверки") ||
+			strings.HasPrefix(line, "Данная информация") ||
+			strings.HasPrefix(line, "С0082") ||
+			strings.HasPrefix(line, "Телефон") ||
+			strings.HasPrefix(line, "Факс") ||
+			strings.HasPrefix(line, "E-mail") ||
+			strings.HasPrefix(line, "www.") ||
+			isMbankHolderLine(line, statement.Holder) ||
+			strings.Contains(line, "KGS KGS") {
+			continue
+		}
+
+		// Check if line starts with date-time
+		if dateTimePattern.MatchString(line) {
+			matches := dateTimePattern.FindStringSubmatch(line)
+			if len(matches) < 3 {
+				continue
+			}
+
+			dateStr := matches[1]
+			timeStr := matches[2]
+
+			// Get the description (rest of the line after datetime)
+			restOfLine := strings.TrimSpace(line[len(matches[0]):])
+
+			// Collect full description and amount
+			// Description might continue on next lines, amount is at the end
+			fullText := restOfLine
+			startLine := i + 1
+
+			// Look ahead for continuation lines (lines that don't start with date)
+			for j := i + 1; j < len(lines); j++ {
+				nextLine := strings.TrimSpace(lines[j])
+				if nextLine == "" {
+					continue
+				}
+				// If next line starts with a date, stop
+				if dateTimePattern.MatchString(nextLine) {
+					break
+				}
+				// Skip obvious header/footer lines
+				if strings.HasPrefix(nextLine, "Всего") ||
+					strings.HasPrefix(nextLine, "Баланс") ||
+					strings.HasPrefix(nextLine, "Для проверки") ||
+					strings.HasPrefix(nextLine, "Данная информация") {
+					break
+				}
+				// A page break repeats the client block between the lines
+				// of an operation
+				if strings.HasPrefix(nextLine, "Клиент") || isMbankHolderLine(nextLine, statement.Holder) {
+					continue
+				}
+				fullText += " " + nextLine
+				i = j // Skip these lines in main loop
+			}
+			endLine := i + 1
+
+			// Try to extract amount from the end
+			amountMatches := amountPattern.FindStringSubmatch(fullText)
+			if len(amountMatches) < 2 {
+				tracker.reject(startLine, endLine, "no amount at the end of the operation")
+				continue
+			}
+
+			amountStr := amountMatches[1]
+			amount, err := parseMbankAmount(amountStr)
+			if err != nil {
+				tracker.reject(startLine, endLine, fmt.Sprintf("invalid amount %q", strings.TrimSpace(amountStr)))
+				continue
+			}
+
+			// Skip if amount is 0
+			if amount == 0 {
+				tracker.reject(startLine, endLine, "zero amount")
+				continue
+			}
+
+			// Description is everything before the amount
+			amountIdx := strings.LastIndex(fullText, amountMatches[0])
+			description := strings.TrimSpace(fullText[:amountIdx])
+
+			// Parse datetime
+			dateTime, err := time.Parse("02.01.2006 15:04", dateStr+" "+timeStr)
+			if err != nil {
+				tracker.reject(startLine, endLine, fmt.Sprintf("invalid date %q", dateStr+" "+timeStr))
+				continue
+			}
+
+			tracker.consume(startLine, endLine)
+			transactions = append(transactions, Transaction{
+				DateTime:    dateTime,
+				Description: description,
+				Merchant:    NormalizeMerchant(description),
+				Amount:      NewMoney(amount, statement.Currency),
+				Bank:        p.BankName(),
+				RawLine:     line,
+				Source:      Source{StartLine: startLine, EndLine: endLine},
+			})
+		}
+	}
+
+	for i := range transactions {
+		transactions[i].Account = statement.Account
+	}
+
+	result := tracker.result(transactions)
+	result.Statement = statement
+	return result, nil
+}
+
+var (
+	// mbankPeriodPattern matches the two dates of "За период 01.12.2025 - 31.12.2025".
+	mbankPeriodPattern = regexp.MustCompile(`(\d{2}\.\d{2}\.\d{4})\D+(\d{2}\.\d{2}\.\d{4})`)
+	// mbankGeneratedPattern matches "25.12.2025 14:30" or "25.12.2025 14:30:15".
+	mbankGeneratedPattern = regexp.MustCompile(`(\d{2}\.\d{2}\.\d{4})(?:\s+(\d{2}:\d{2})(:\d{2})?)?`)
+	// mbankAccountPattern matches an account or card number of at least
+	// eight digits, possibly grouped by spaces or dashes and masked with
+	// asterisks by the bank.
+	mbankAccountPattern = regexp.MustCompile(`\d[\d\s*-]{6,}\d`)
+	// mbankTotalPattern matches an amount like "1 018,00" in a totals line.
+	// Thousands must be grouped by three so a preceding date's year is not
+	// taken as part of the amount.
+	mbankTotalPattern = regexp.MustCompile(`-?\s*(?:\d{1,3}(?:\s\d{3})+|\d+),\d{2}`)
+)
+
+// parseMbankStatement extracts the account, currency, holder, period and
+// generation time from the statement header, and the balances and debit and
+// credit totals declared in its header and footer.
+func parseMbankStatement(lines []string) Statement {
+	statement := Statement{Currency: "KGS"}
+	totals := &statement.Totals
+
+	for i, raw := range lines {
+		line := strings.TrimSpace(raw)
+		switch {
+		case strings.HasPrefix(line, "Выписка по счету"):
+			rest := strings.TrimPrefix(line, "Выписка по счету")
+			if m := mbankAccountPattern.FindString(rest); m != "" {
+				statement.Account = MaskAccount(m)
+			}
+			for _, field := range strings.FieldsFunc(rest, func(r rune) bool { return !unicode.IsLetter(r) }) {
+				if isCurrencyCode(field) {
+					statement.Currency = field
+				}
+			}
+		case strings.HasPrefix(line, "За период"):
+			if m := mbankPeriodPattern.FindStringSubmatch(line); m != nil {
+				start, err1 := time.Parse("02.01.2006", m[1])
+				end, err2 := time.Parse("02.01.2006", m[2])
+				if err1 == nil && err2 == nil {
+					statement.PeriodStart, statement.PeriodEnd = start, end
+				}
+			}
+		case strings.HasPrefix(line, "Дата формирования"):
+			if m := mbankGeneratedPattern.FindStringSubmatch(line); m != nil {
+				layout, value := "02.01.2006", m[1]
+				if m[2] != "" {
+					layout, value = layout+" 15:04", value+" "+m[2]
+				}
+				if m[3] != "" {
+					layout, value = layout+":05", value+m[3]
+				}
+				if t, err := time.Parse(layout, value); err == nil {
+					statement.GeneratedAt = t
+				}
+			}
+		case strings.HasPrefix(line, "Клиент"):
+			statement.Holder = mbankLabelValue(lines, i, "Клиент")
+		case strings.HasPrefix(line, "Всего списаний"):
+			if amount, ok := mbankTotalAmount(lines, i); ok {
+				totals.TotalDebits = NewMoney(absMinor(amount), statement.Currency)
+			}
+		case strings.HasPrefix(line, "Всего пополнений"):
+			if amount, ok := mbankTotalAmount(lines, i); ok {
+				totals.TotalCredits = NewMoney(absMinor(amount), statement.Currency)
+			}
+		case strings.HasPrefix(line, "Баланс"):
+			lower := strings.ToLower(line)
+			amount, ok := mbankTotalAmount(lines, i)
+			switch {
+			case !ok:
+			case strings.Contains(lower, "начал") || strings.Contains(lower, "входящ"):
+				totals.OpeningBalance = NewMoney(amount, statement.Currency)
+			case strings.Contains(lower, "конец") || strings.Contains(lower, "исходящ"):
+				totals.ClosingBalance = NewMoney(amount, statement.Currency)
+			}
+		}
+	}
+	return statement
+}
+
+// mbankLabelValue returns the text after label on line i, with any colon
+// removed, or the next non-empty line when the label stands alone.
+func mbankLabelValue(lines []string, i int, label string) string {
+	value := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(lines[i]), label))
+	value = strings.TrimSpace(strings.TrimPrefix(value, ":"))
+	if value != "" {
+		return value
+	}
+	for j := i + 1; j < len(lines); j++ {
+		if next := strings.TrimSpace(lines[j]); next != "" {
+			return next
+		}
+	}
+	return ""
+}
+
+// isMbankHolderLine reports whether line is the account holder's name from
+// the "Клиент" block.
+func isMbankHolderLine(line, holder string) bool {
+	return holder != "" && line == holder
+}
+
+// mbankTotalAmount returns the amount after the label on line i or, when the
+// PDF put it on a line of its own, on the next non-empty line.
+func mbankTotalAmount(lines []string, i int) (int64, bool) {
+	if _, value, ok := strings.Cut(lines[i], ":"); ok {
+		if amount, ok := parseMbankTotal(value); ok {
+			return amount, true
+		}
+	} else if amount, ok := parseMbankTotal(lines[i]); ok {
+		return amount, true
+	}
+
+	for j := i + 1; j < len(lines); j++ {
+		next := strings.TrimSpace(lines[j])
+		if next == "" {
+			continue
+		}
+		if m := mbankTotalPattern.FindString(next); m != "" && strings.TrimSpace(m) == next {
+			return parseMbankTotal(next)
+		}
+		break
+	}
+	return 0, false
+}
+
+// parseMbankTotal parses the last amount in s.
+func parseMbankTotal(s string) (int64, bool) {
+	matches := mbankTotalPattern.FindAllString(s, -1)
+	if len(matches) == 0 {
+		return 0, false
+	}
+	amount, err := parseMbankAmount(matches[len(matches)-1])
+	return amount, err == nil
+}
+
+// parseMbankAmount parses amounts like "- 1 018,00" into minor units.
+func parseMbankAmount(s string) (int64, error) {
+	// Remove spaces
+	s = strings.ReplaceAll(s, " ", "")
+	// Replace comma with dot for decimal
+	s = strings.ReplaceAll(s, ",", ".")
+	s = strings.TrimSpace(s)
+
+	return ParseAmount(s)
+}
@@ -0,0 +1,478 @@
+package dupay
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMbankParser_ID(t *testing.T) {
+	parser := NewMbankParser()
+	expected := "mbank"
+	if parser.ID() != expected {
+		t.Errorf("expected %q, got %q", expected, parser.ID())
+	}
+}
+
+func TestMbankParser_BankName(t *testing.T) {
+	parser := NewMbankParser()
+	expected := "Mbank"
+	if parser.BankName() != expected {
+		t.Errorf("expected %q, got %q", expected, parser.BankName())
+	}
+}
+
+func TestMbankParser_Score(t *testing.T) {
+	parser := NewMbankParser()
+
+	tests := []struct {
+		name     string
+		content  string
+		expected bool
+	}{
+		{
+			name:     "contains mbank.kg",
+			content:  "Statement from mbank.kg",
+			expected: true,
+		},
+		{
+			name:     "contains Mbank",
+			content:  "Mbank statement for January",
+			expected: true,
+		},
+		{
+			name:     "contains МБАНК (Cyrillic)",
+			content:  "Выписка МБАНК за январь",
+			expected: true,
+		},
+		{
+			name:     "no Mbank references",
+			content:  "Statement from Another Bank",
+			expected: false,
+		},
+		{
+			name:     "empty content",
+			content:  "",
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := parser.Score(tt.content) > 0
+			if result != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestMbankParser_Markers(t *testing.T) {
+	parser := NewMbankParser()
+
+	markers := parser.Markers()
+	if len(markers) == 0 {
+		t.Fatal("expected at least one marker")
+	}
+	for _, m := range markers {
+		if score := parser.Score("statement " + m.Text + " text"); score < m.Weight {
+			t.Errorf("marker %q scored %d, expected at least %d", m.Text, score, m.Weight)
+		}
+	}
+
+	// Callers must not be able to change detection through the returned slice
+	markers[0].Text = "changed"
+	if parser.Markers()[0].Text != "mbank.kg" {
+		t.Error("Markers returned the parser's internal slice")
+	}
+}
+
+func TestMbankParser_Parse(t *testing.T) {
+	parser := NewMbankParser()
+
+	tests := []struct {
+		name             string
+		content          string
+		expectedCount    int
+		expectedAmount   int64
+		expectedCurrency string
+	}{
+		{
+			name:          "empty content",
+			content:       "",
+			expectedCount: 0,
+		},
+		{
+			name: "single transaction",
+			content: `Mbank Statement
+24.12.2025 12:02 Оплата в магазине - 1 018,00`,
+			expectedCount:    1,
+			expectedAmount:   -101800,
+			expectedCurrency: "KGS",
+		},
+		{
+			name: "positive transaction",
+			content: `Mbank Statement
+24.12.2025 14:30 Пополнение счета 5 000,00`,
+			expectedCount:    1,
+			expectedAmount:   500000,
+			expectedCurrency: "KGS",
+		},
+		{
+			name: "multiple transactions",
+			content: `Mbank Statement
+24.12.2025 10:00 First payment - 500,00
+24.12.2025 11:00 Second payment - 1 000,00`,
+			expectedCount: 2,
+		},
+		{
+			name: "skip header lines",
+			content: `Mbank Statement
+Выписка по счету
+За период с 01.12.2025 по 31.12.2025
+Дата операции
+Описание операции
+Сумма операции
+24.12.2025 10:00 Real transaction - 500,00`,
+			expectedCount:  1,
+			expectedAmount: -50000,
+		},
+		{
+			name: "skip footer lines",
+			content: `Mbank Statement
+24.12.2025 10:00 Payment - 500,00
+Всего списаний: 500,00
+Для проверки подлинности`,
+			expectedCount:  1,
+			expectedAmount: -50000,
+		},
+		{
+			name: "multiline description",
+			content: `Mbank Statement
+24.12.2025 10:00 First line of
+description continues here - 500,00`,
+			expectedCount: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parser.Parse(tt.content)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			transactions := result.Transactions
+			if len(transactions) != tt.expectedCount {
+				t.Errorf("expected %d transactions, got %d", tt.expectedCount, len(transactions))
+			}
+			if tt.expectedCount > 0 && tt.expectedAmount != 0 {
+				if transactions[0].Amount.Minor != tt.expectedAmount {
+					t.Errorf("expected amount %v, got %v", tt.expectedAmount, transactions[0].Amount.Minor)
+				}
+			}
+			if tt.expectedCount > 0 && tt.expectedCurrency != "" {
+				if transactions[0].Amount.Currency != tt.expectedCurrency {
+					t.Errorf("expected currency %v, got %v", tt.expectedCurrency, transactions[0].Amount.Currency)
+				}
+			}
+		})
+	}
+}
+
+func TestMbankParser_SourceLines(t *testing.T) {
+	parser := NewMbankParser()
+
+	content := `Mbank Statement
+24.12.2025 10:00 Payment - 500,00
+24.12.2025 11:00 First line of
+
+description continues here - 500,00
+Всего списаний: 1 000,00`
+
+	result, err := parser.Parse(content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	transactions := result.Transactions
+	if len(transactions) != 2 {
+		t.Fatalf("expected 2 transactions, got %d", len(transactions))
+	}
+
+	expected := []Source{
+		{StartLine: 2, EndLine: 2},
+		{StartLine: 3, EndLine: 5},
+	}
+	for i, src := range expected {
+		if transactions[i].Source != src {
+			t.Errorf("transaction %d: expected source %+v, got %+v", i, src, transactions[i].Source)
+		}
+	}
+}
+
+func TestMbankParser_Warnings(t *testing.T) {
+	parser := NewMbankParser()
+
+	content := `Mbank Statement
+24.12.2025 10:00 Payment - 500,00
+24.12.2025 11:00 Cancelled payment 0,00
+24.12.2025 12:00 Row without amount
+Всего списаний: 500,00`
+
+	result, err := parser.Parse(content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Transactions) != 1 {
+		t.Fatalf("expected 1 transaction, got %d", len(result.Transactions))
+	}
+
+	expected := []ParseWarning{
+		{Source: Source{StartLine: 3, EndLine: 3}, Text: "24.12.2025 11:00 Cancelled payment 0,00", Reason: "zero amount"},
+		{Source: Source{StartLine: 4, EndLine: 4}, Text: "24.12.2025 12:00 Row without amount", Reason: "no amount at the end of the operation"},
+	}
+	if !reflect.DeepEqual(result.Warnings, expected) {
+		t.Errorf("expected warnings %+v, got %+v", expected, result.Warnings)
+	}
+
+	expectedLines := LineStats{Consumed: 1, Skipped: 2, Rejected: 2}
+	if result.Lines != expectedLines {
+		t.Errorf("expected line stats %+v, got %+v", expectedLines, result.Lines)
+	}
+}
+
+func TestMbankParser_Statement(t *testing.T) {
+	content := `Выписка по счету 1030 1200 0012 3456 KGS
+За период: 01.12.2025 - 31.12.2025
+Дата формирования: 25.12.2025 14:30:15
+Клиент
+ИВАНОВА АЙГУЛЬ
+Баланс на начало периода: 10 000,00
+24.12.2025 10:00 Payment - 1 018,00
+24.12.2025 11:00 Пополнение счета 5 000,00
+Баланс на конец периода: 13 982,00
+Всего списаний: 1 018,00
+Всего пополнений:
+5 000,00`
+
+	result, err := NewMbankParser().Parse(content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := Statement{
+		Bank:        "Mbank",
+		Account:     "1030********3456",
+		Currency:    "KGS",
+		Holder:      "ИВАНОВА АЙГУЛЬ",
+		PeriodStart: time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:   time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC),
+		GeneratedAt: time.Date(2025, 12, 25, 14, 30, 15, 0, time.UTC),
+		Totals: StatementTotals{
+			OpeningBalance: NewMoney(1000000, "KGS"),
+			ClosingBalance: NewMoney(1398200, "KGS"),
+			TotalDebits:    NewMoney(101800, "KGS"),
+			TotalCredits:   NewMoney(500000, "KGS"),
+		},
+	}
+	if result.Statement != expected {
+		t.Errorf("expected %+v, got %+v", expected, result.Statement)
+	}
+	if err := CheckTotals(result.Statement.Totals, result.Transactions); err != nil {
+		t.Errorf("unexpected mismatch: %v", err)
+	}
+	for _, tx := range result.Transactions {
+		if tx.Account != expected.Account {
+			t.Errorf("expected account %q on every transaction, got %q", expected.Account, tx.Account)
+		}
+		if tx.Amount.Currency != "KGS" {
+			t.Errorf("expected currency KGS on every transaction, got %q", tx.Amount.Currency)
+		}
+	}
+
+	// A USD account's operations and totals are in dollars
+	usd := `Выписка по счету 1030 1200 0012 9876 USD
+24.12.2025 10:00 Payment - 10,00
+24.12.2025 11:00 Пополнение счета 50,00
+Всего списаний: 10,00`
+
+	result, err = NewMbankParser().Parse(usd)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Statement.Currency != "USD" || result.Statement.Totals.TotalDebits != NewMoney(1000, "USD") {
+		t.Errorf("expected a USD statement with 10.00 USD of debits, got %+v", result.Statement)
+	}
+	if len(result.Transactions) != 2 {
+		t.Fatalf("expected 2 transactions, got %d", len(result.Transactions))
+	}
+	for i, tx := range result.Transactions {
+		if tx.Amount.Currency != "USD" {
+			t.Errorf("transaction %d: expected currency USD, got %q", i, tx.Amount.Currency)
+		}
+	}
+}
+
+func TestMbankParser_Holders(t *testing.T) {
+	tests := []struct {
+		name         string
+		content      string
+		holder       string
+		descriptions []string
+	}{
+		{
+			name: "holder on the line after the label",
+			content: `Клиент
+ИВАНОВА АЙГУЛЬ
+24.12.2025 10:00 Globus - 1 018,00`,
+			holder:       "ИВАНОВА АЙГУЛЬ",
+			descriptions: []string{"Globus"},
+		},
+		{
+			name: "holder after a colon",
+			content: `Клиент: ТОКТОГУЛОВ БАКЫТ
+24.12.2025 10:00 Globus - 1 018,00`,
+			holder:       "ТОКТОГУЛОВ БАКЫТ",
+			descriptions: []string{"Globus"},
+		},
+		{
+			name: "holder repeated on the next page",
+			content: `Клиент
+ПЕТРОВ ИВАН
+24.12.2025 10:00 Оплата услуг
+Клиент
+ПЕТРОВ ИВАН
+Beeline - 200,00
+24.12.2025 11:00 Globus - 1 018,00`,
+			holder:       "ПЕТРОВ ИВАН",
+			descriptions: []string{"Оплата услуг Beeline", "Globus"},
+		},
+		{
+			name: "merchant sharing the holder's surname is kept",
+			content: `Клиент
+РАСУЛОВ ЭМИРЛАН
+24.12.2025 10:00 ИП РАСУЛОВ - 500,00
+24.12.2025 11:00 Перевод
+РАСУЛОВ ЭМИРЛАН - 300,00`,
+			holder:       "РАСУЛОВ ЭМИРЛАН",
+			descriptions: []string{"ИП РАСУЛОВ", "Перевод РАСУЛОВ ЭМИРЛАН"},
+		},
+		{
+			name:         "no client block",
+			content:      `24.12.2025 10:00 ИП РАСУЛОВ - 500,00`,
+			descriptions: []string{"ИП РАСУЛОВ"},
+		},
+	}
+
+	parser := NewMbankParser()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parser.Parse(tt.content)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Statement.Holder != tt.holder {
+				t.Errorf("expected holder %q, got %q", tt.holder, result.Statement.Holder)
+			}
+
+			var descriptions []string
+			for _, tx := range result.Transactions {
+				descriptions = append(descriptions, tx.Description)
+			}
+			if !reflect.DeepEqual(descriptions, tt.descriptions) {
+				t.Errorf("expected descriptions %q, got %q", tt.descriptions, descriptions)
+			}
+		})
+	}
+}
+
+func TestParseMbankAmount(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected int64
+	}{
+		{
+			name:     "simple amount",
+			input:    "100,00",
+			expected: 10000,
+		},
+		{
+			name:     "negative amount",
+			input:    "-100,00",
+			expected: -10000,
+		},
+		{
+			name:     "negative with space after minus",
+			input:    "- 100,00",
+			expected: -10000,
+		},
+		{
+			name:     "amount with space separator",
+			input:    "1 000,00",
+			expected: 100000,
+		},
+		{
+			name:     "large amount with spaces",
+			input:    "1 234 567,89",
+			expected: 123456789,
+		},
+		{
+			name:     "negative with spaces",
+			input:    "- 1 018,00",
+			expected: -101800,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parseMbankAmount(tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestMbankParser_TransactionDetails(t *testing.T) {
+	parser := NewMbankParser()
+
+	content := `Mbank Statement
+24.12.2025 12:02 Оплата покупки в магазине - 1 500,00`
+
+	result, err := parser.Parse(content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	transactions := result.Transactions
+	if len(transactions) != 1 {
+		t.Fatalf("expected 1 transaction, got %d", len(transactions))
+	}
+
+	tx := transactions[0]
+
+	// Check bank name
+	if tx.Bank != "Mbank" {
+		t.Errorf("expected bank 'Mbank', got %q", tx.Bank)
+	}
+
+	// Check currency
+	if tx.Amount.Currency != "KGS" {
+		t.Errorf("expected currency 'KGS', got %q", tx.Amount.Currency)
+	}
+
+	// Check description contains expected text
+	if tx.Description == "" {
+		t.Error("expected non-empty description")
+	}
+
+	// Check datetime
+	if tx.DateTime.Day() != 24 || tx.DateTime.Month() != 12 || tx.DateTime.Year() != 2025 {
+		t.Errorf("unexpected date: %v", tx.DateTime)
+	}
+	if tx.DateTime.Hour() != 12 || tx.DateTime.Minute() != 2 {
+		t.Errorf("unexpected time: %v", tx.DateTime)
+	}
+}
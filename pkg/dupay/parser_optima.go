@@ -0,0 +1,269 @@
+package dupay
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// optimaMarkers are text fragments that identify Optima Bank statements.
+var optimaMarkers = []Marker{
+	{Text: "optimabank.kg", Weight: 60},
+	{Text: "Optima Bank", Weight: 60},
+	{Text: "OptimaBank", Weight: 60},
+}
+
+// OptimaParser parses Optima Bank PDF statements.
+type OptimaParser struct{}
+
+// NewOptimaParser creates a parser for Optima Bank statements.
+func NewOptimaParser() *OptimaParser {
+	return &OptimaParser{}
+}
+
+// BankName returns the human-readable name of the bank.
+func (p *OptimaParser) BankName() string {
+	return "Optima Bank"
+}
+
+// ID returns the identifier used to select this parser by hand.
+func (p *OptimaParser) ID() string {
+	return "optima"
+}
+
+// Score rates how much the content looks like an Optima Bank statement.
+func (p *OptimaParser) Score(content string) int {
+	return scoreMarkers(content, optimaMarkers)
+}
+
+// Markers returns the weighted text fragments that identify an Optima Bank statement.
+func (p *OptimaParser) Markers() []Marker {
+	return slices.Clone(optimaMarkers)
+}
+
+// normalizeSpaces replaces non-breaking spaces and other whitespace with regular spaces
+func normalizeSpaces(s string) string {
+	// Replace non-breaking space (U+00A0) with regular space
+	s = strings.ReplaceAll(s, "\u00a0", " ")
+	// Replace other unicode spaces
+	s = strings.ReplaceAll(s, "\u2007", " ") // figure space
+	s = strings.ReplaceAll(s, "\u202f", " ") // narrow no-break space
+	return s
+}
+
+// Parse extracts transactions from Optima Bank statement text.
+func (p *OptimaParser) Parse(content string) (ParseResult, error) {
+	var transactions []Transaction
+
+	// Normalize spaces in content
+	content = normalizeSpaces(content)
+
+	lines := strings.Split(content, "\n")
+	tracker := newLineTracker(lines)
+
+	// Regex patterns
+	datePattern := regexp.MustCompile(`^(\d{2}\.\d{2}\.\d{4})\s*$`)
+	timePattern := regexp.MustCompile(`^(\d{2}:\d{2})$`)
+	// Amount can be negative like "-1 965.84" or positive like "318 273.38"
+	amountPattern := regexp.MustCompile(`^(-?[\d\s]+(?:\.\d+)?)\s*$`)
+
+	accountCurrency := optimaAccountCurrency(lines, amountPattern)
+
+	i := 0
+	for i < len(lines) {
+		line := strings.TrimSpace(lines[i])
+
+		// Look for date pattern
+		if !datePattern.MatchString(line) {
+			i++
+			continue
+		}
+
+		currentDate := strings.TrimSpace(line)
+		startLine := i + 1
+		i++
+
+		// Next should be time
+		if i >= len(lines) {
+			break
+		}
+		timeLine := strings.TrimSpace(lines[i])
+		if !timePattern.MatchString(timeLine) {
+			tracker.reject(startLine, startLine, "date not followed by a time")
+			continue
+		}
+		currentTime := timeLine
+		i++
+
+		// Collect description lines until we hit an amount
+		var descLines []string
+		var amount, original Money
+		foundAmount := false
+
+		for i < len(lines) {
+			line := strings.TrimSpace(lines[i])
+
+			// Skip empty lines
+			if line == "" {
+				i++
+				continue
+			}
+
+			// An amount is a number followed by a currency code on the next non-empty line
+			if amountPattern.MatchString(line) {
+				if currency, next := optimaCurrencyAfter(lines, i); currency != "" {
+					amt, amtErr := parseOptimaAmount(line)
+					i = next
+
+					if currency == accountCurrency {
+						amount = NewMoney(amt, currency)
+						foundAmount = amtErr == nil
+						break
+					}
+
+					// Foreign-currency purchase: keep the original amount and
+					// look for the account-currency amount that follows it
+					original = NewMoney(amt, currency)
+					for i < len(lines) {
+						l := strings.TrimSpace(lines[i])
+						if l == "" {
+							i++
+							continue
+						}
+						if amountPattern.MatchString(l) {
+							if c, n := optimaCurrencyAfter(lines, i); c == accountCurrency {
+								amt, err := parseOptimaAmount(l)
+								amount = NewMoney(amt, c)
+								i = n
+								foundAmount = err == nil && amtErr == nil
+								break
+							}
+						}
+						i++
+					}
+					break
+				}
+			}
+
+			// Skip page numbers and headers
+			if strings.Contains(line, "/6") ||
+				line == "Date" || line == "Details" || line == "of" ||
+				line == "operations" || line == "Operation" || line == "amount" ||
+				line == "Fee" || line == "0" {
+				i++
+				continue
+			}
+
+			// This is part of description
+			descLines = append(descLines, line)
+			i++
+		}
+
+		if !foundAmount {
+			tracker.reject(startLine, lastNonEmptyLine(lines, startLine, i), "no valid amount")
+			continue
+		}
+
+		// The fee column follows: a number and a currency code
+		var fee Money
+		for i < len(lines) && strings.TrimSpace(lines[i]) == "" {
+			i++
+		}
+		if i < len(lines) && amountPattern.MatchString(strings.TrimSpace(lines[i])) {
+			if currency, next := optimaCurrencyAfter(lines, i); currency != "" {
+				if amt, err := parseOptimaAmount(strings.TrimSpace(lines[i])); err == nil && amt != 0 {
+					fee = NewMoney(absMinor(amt), currency)
+				}
+				i = next
+			}
+		}
+
+		// The transaction ends at the last non-empty line consumed
+		endLine := lastNonEmptyLine(lines, startLine, i)
+
+		// Parse datetime
+		dateTime, err := time.Parse("02.01.2006 15:04", currentDate+" "+currentTime)
+		if err != nil {
+			tracker.reject(startLine, endLine, fmt.Sprintf("invalid date %q", currentDate+" "+currentTime))
+			continue
+		}
+
+		tracker.consume(startLine, endLine)
+		description := strings.Join(descLines, " ")
+
+		transactions = append(transactions, Transaction{
+			DateTime:       dateTime,
+			Description:    description,
+			Merchant:       NormalizeMerchant(description),
+			Amount:         amount,
+			OriginalAmount: original,
+			Fee:            fee,
+			Bank:           p.BankName(),
+			RawLine:        description,
+			Source:         Source{StartLine: startLine, EndLine: endLine},
+		})
+	}
+
+	result := tracker.result(transactions)
+	result.Statement = Statement{Bank: p.BankName(), Currency: accountCurrency}
+	return result, nil
+}
+
+// optimaCurrencyAfter returns the currency code on the next non-empty line
+// after line i and the index of the line following it, or "" if that line is
+// not a currency code.
+func optimaCurrencyAfter(lines []string, i int) (string, int) {
+	j := i + 1
+	for j < len(lines) && strings.TrimSpace(lines[j]) == "" {
+		j++
+	}
+	if j < len(lines) {
+		if code := strings.TrimSpace(lines[j]); isCurrencyCode(code) {
+			return code, j + 1
+		}
+	}
+	return "", i
+}
+
+// optimaAccountCurrency determines the currency of the statement's account.
+// Every row lists its amount and fee in the account currency, while only
+// foreign purchases add an amount in another currency, so the most frequent
+// currency wins. Statements without amounts default to KGS.
+func optimaAccountCurrency(lines []string, amountPattern *regexp.Regexp) string {
+	counts := make(map[string]int)
+	for i, line := range lines {
+		if !amountPattern.MatchString(strings.TrimSpace(line)) {
+			continue
+		}
+		if code, _ := optimaCurrencyAfter(lines, i); code != "" {
+			counts[code]++
+		}
+	}
+	return mostFrequentCurrency(counts)
+}
+
+// mostFrequentCurrency returns the currency with the highest count, KGS if
+// there are none. Ties go to KGS, then to the alphabetically first code.
+func mostFrequentCurrency(counts map[string]int) string {
+	best := "KGS"
+	for _, code := range slices.Sorted(maps.Keys(counts)) {
+		if counts[code] > counts[best] {
+			best = code
+		}
+	}
+	return best
+}
+
+// parseOptimaAmount parses amounts like "-1 965.84" into minor units.
+func parseOptimaAmount(s string) (int64, error) {
+	// Normalize spaces first
+	s = normalizeSpaces(s)
+	// Remove all spaces (thousands separator)
+	s = strings.ReplaceAll(s, " ", "")
+	s = strings.TrimSpace(s)
+
+	return ParseAmount(s)
+}
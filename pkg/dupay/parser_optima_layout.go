@@ -0,0 +1,183 @@
+package dupay
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// The columns of the Optima Bank transaction table, in header order.
+const (
+	optimaDateColumn = iota
+	optimaDetailsColumn
+	optimaAmountColumn
+	optimaFeeColumn
+)
+
+// optimaHeaders are the first words of the transaction table's column headers.
+var optimaHeaders = []string{"Date", "Details", "Operation", "Fee"}
+
+var (
+	optimaLayoutDatePattern = regexp.MustCompile(`^\d{2}\.\d{2}\.\d{4}`)
+	optimaLayoutTimePattern = regexp.MustCompile(`\b\d{2}:\d{2}\b`)
+	// optimaLayoutAmountPattern matches an amount and its currency, like
+	// "-1 965.84 KGS".
+	optimaLayoutAmountPattern = regexp.MustCompile(`(-?\d[\d ]*(?:\.\d+)?) ?([A-Z]{3})\b`)
+	optimaPageNumberPattern   = regexp.MustCompile(`^\d+\s*/\s*\d+$`)
+)
+
+// ParseLayout extracts transactions from the table rows of an Optima Bank
+// statement. The columns are taken from the table header, so the date,
+// description, amount and fee of an operation are read from their own
+// columns rather than guessed from the order of the plain text.
+func (p *OptimaParser) ParseLayout(rows []Row) (ParseResult, error) {
+	lines := make([]string, len(rows))
+	for i, row := range rows {
+		lines[i] = row.Text()
+	}
+	tracker := newLineTracker(lines)
+
+	var transactions []Transaction
+	var columns Columns
+	// operation holds the indexes of the rows of the current operation
+	var operation []int
+	flush := func() {
+		if len(operation) == 0 {
+			return
+		}
+		start, end := operation[0]+1, operation[len(operation)-1]+1
+		tx, reason := p.layoutTransaction(rows, operation, columns)
+		operation = nil
+		if reason != "" {
+			tracker.reject(start, end, reason)
+			return
+		}
+		tracker.consume(start, end)
+		tx.Source = Source{StartLine: start, EndLine: end}
+		transactions = append(transactions, tx)
+	}
+
+	for i, row := range rows {
+		// The header is repeated on every page
+		if header, ok := optimaHeaderColumns(row); ok {
+			flush()
+			columns = header
+			continue
+		}
+		if columns == nil || optimaPageNumberPattern.MatchString(row.Text()) {
+			continue
+		}
+
+		date := columns.Split(row)[optimaDateColumn]
+		switch {
+		case optimaLayoutDatePattern.MatchString(date):
+			flush()
+			operation = []int{i}
+		case len(operation) == 0:
+		case date != "" && !optimaLayoutTimePattern.MatchString(date):
+			// Other text in the date column ends the table
+			flush()
+		default:
+			operation = append(operation, i)
+		}
+	}
+	flush()
+
+	if columns == nil {
+		return ParseResult{}, ErrNoTable
+	}
+
+	counts := make(map[string]int)
+	for _, tx := range transactions {
+		counts[tx.Amount.Currency]++
+	}
+	result := tracker.result(transactions)
+	result.Statement = Statement{Bank: p.BankName(), Currency: mostFrequentCurrency(counts)}
+	return result, nil
+}
+
+// optimaHeaderColumns returns the columns of the transaction table if row is
+// its header.
+func optimaHeaderColumns(row Row) (Columns, bool) {
+	columns := make(Columns, 0, len(optimaHeaders))
+	for _, cell := range row.Cells {
+		if n := len(columns); n < len(optimaHeaders) && strings.HasPrefix(cell.Text, optimaHeaders[n]) {
+			columns = append(columns, cell.X)
+		}
+	}
+	return columns, len(columns) == len(optimaHeaders)
+}
+
+// layoutTransaction builds a transaction from the rows of one operation. It
+// returns the reason when the operation cannot be parsed.
+func (p *OptimaParser) layoutTransaction(rows []Row, operation []int, columns Columns) (Transaction, string) {
+	texts := make([][]string, len(columns))
+	for _, i := range operation {
+		for col, text := range columns.Split(rows[i]) {
+			if text != "" {
+				texts[col] = append(texts[col], text)
+			}
+		}
+	}
+	column := func(col int) string {
+		return strings.Join(texts[col], " ")
+	}
+
+	date := optimaLayoutDatePattern.FindString(column(optimaDateColumn))
+	clock := optimaLayoutTimePattern.FindString(column(optimaDateColumn))
+	if clock == "" {
+		return Transaction{}, "date not followed by a time"
+	}
+
+	// A foreign-currency purchase lists its original amount before the
+	// amount charged to the account
+	amounts, ok := optimaLayoutAmounts(column(optimaAmountColumn))
+	if !ok || len(amounts) == 0 {
+		return Transaction{}, "no valid amount"
+	}
+	amount := amounts[len(amounts)-1]
+	var original Money
+	if len(amounts) > 1 && amounts[0].Currency != amount.Currency {
+		original = amounts[0]
+	}
+
+	var fee Money
+	if fees, ok := optimaLayoutAmounts(column(optimaFeeColumn)); ok && len(fees) > 0 && fees[0].Minor != 0 {
+		fee = NewMoney(absMinor(fees[0].Minor), fees[0].Currency)
+	}
+
+	dateTime, err := time.Parse("02.01.2006 15:04", date+" "+clock)
+	if err != nil {
+		return Transaction{}, fmt.Sprintf("invalid date %q", date+" "+clock)
+	}
+
+	description := column(optimaDetailsColumn)
+	return Transaction{
+		DateTime:       dateTime,
+		Description:    description,
+		Merchant:       NormalizeMerchant(description),
+		Amount:         amount,
+		OriginalAmount: original,
+		Fee:            fee,
+		Bank:           p.BankName(),
+		RawLine:        description,
+	}, ""
+}
+
+// optimaLayoutAmounts parses the amounts and currencies in the text of an
+// amount or fee column. It reports false if an amount is malformed.
+func optimaLayoutAmounts(text string) ([]Money, bool) {
+	var amounts []Money
+	for _, m := range optimaLayoutAmountPattern.FindAllStringSubmatch(text, -1) {
+		if !isCurrencyCode(m[2]) {
+			continue
+		}
+		amount, err := parseOptimaAmount(strings.TrimSpace(m[1]))
+		if err != nil {
+			return nil, false
+		}
+		amounts = append(amounts, NewMoney(amount, m[2]))
+	}
+	return amounts, true
+}
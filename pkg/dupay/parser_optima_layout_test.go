@@ -0,0 +1,104 @@
+package dupay
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// optimaRow builds a row with one cell per non-empty text, placed in the
+// date, details, amount and fee columns of optimaTestHeader.
+func optimaRow(page int, texts ...string) Row {
+	xs := []float64{40, 110, 320, 420}
+	row := Row{Page: page}
+	for i, text := range texts {
+		if text != "" {
+			row.Cells = append(row.Cells, Cell{X: xs[i] + 2, Text: text})
+		}
+	}
+	return row
+}
+
+var optimaTestHeader = Row{Page: 1, Cells: []Cell{
+	{X: 40, Text: "Date"},
+	{X: 110, Text: "Details of"},
+	{X: 320, Text: "Operation"},
+	{X: 420, Text: "Fee"},
+}}
+
+func TestOptimaParser_ParseLayout(t *testing.T) {
+	rows := []Row{
+		{Page: 1, Cells: []Cell{{X: 40, Text: "Optima Bank statement"}}},
+		optimaTestHeader,
+		optimaRow(1, "", "operations", "amount"),
+		optimaRow(1, "15.01.2025", "Payment to", "-1 500.00 KGS", "0 KGS"),
+		optimaRow(1, "10:30", "Globus"),
+		optimaRow(1, "16.01.2025", "NETFLIX.COM", "-15.00 USD", "-25.00 KGS"),
+		optimaRow(1, "09:00", "", "-1 312.50 KGS"),
+		optimaRow(1, "", "1/2"),
+		optimaTestHeader,
+		optimaRow(2, "17.01.2025", "Row without amount"),
+		optimaRow(2, "11:00"),
+		optimaRow(2, "Total", "", "-2 812.50 KGS"),
+	}
+	rows[8].Page = 2
+
+	result, err := NewOptimaParser().ParseLayout(rows)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []Transaction{
+		{
+			DateTime:    time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC),
+			Description: "Payment to Globus",
+			Merchant:    "GLOBUS",
+			Amount:      NewMoney(-150000, "KGS"),
+			Bank:        "Optima Bank",
+			RawLine:     "Payment to Globus",
+			Source:      Source{StartLine: 4, EndLine: 5},
+		},
+		{
+			DateTime:       time.Date(2025, 1, 16, 9, 0, 0, 0, time.UTC),
+			Description:    "NETFLIX.COM",
+			Merchant:       "NETFLIX",
+			Amount:         NewMoney(-131250, "KGS"),
+			OriginalAmount: NewMoney(-1500, "USD"),
+			Fee:            NewMoney(2500, "KGS"),
+			Bank:           "Optima Bank",
+			RawLine:        "NETFLIX.COM",
+			Source:         Source{StartLine: 6, EndLine: 7},
+		},
+	}
+	if !reflect.DeepEqual(result.Transactions, expected) {
+		t.Errorf("expected %+v, got %+v", expected, result.Transactions)
+	}
+
+	expectedWarnings := []ParseWarning{
+		{Source: Source{StartLine: 10, EndLine: 11}, Text: "17.01.2025 Row without amount", Reason: "no valid amount"},
+	}
+	if !reflect.DeepEqual(result.Warnings, expectedWarnings) {
+		t.Errorf("expected warnings %+v, got %+v", expectedWarnings, result.Warnings)
+	}
+
+	if result.Statement.Currency != "KGS" {
+		t.Errorf("expected statement currency KGS, got %q", result.Statement.Currency)
+	}
+	expectedLines := LineStats{Consumed: 4, Skipped: 6, Rejected: 2}
+	if result.Lines != expectedLines {
+		t.Errorf("expected line stats %+v, got %+v", expectedLines, result.Lines)
+	}
+}
+
+func TestOptimaParser_ParseLayoutNoTable(t *testing.T) {
+	rows := []Row{
+		{Page: 1, Cells: []Cell{{X: 40, Text: "15.01.2025"}}},
+		{Page: 1, Cells: []Cell{{X: 40, Text: "10:30"}}},
+	}
+
+	_, err := NewOptimaParser().ParseLayout(rows)
+	if !errors.Is(err, ErrNoTable) {
+		t.Errorf("expected ErrNoTable, got %v", err)
+	}
+}
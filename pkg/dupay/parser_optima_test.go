@@ -0,0 +1,501 @@
+package dupay
+
+import (
+	"testing"
+)
+
+func TestOptimaParser_ID(t *testing.T) {
+	parser := NewOptimaParser()
+	expected := "optima"
+	if parser.ID() != expected {
+		t.Errorf("expected %q, got %q", expected, parser.ID())
+	}
+}
+
+func TestOptimaParser_BankName(t *testing.T) {
+	parser := NewOptimaParser()
+	expected := "Optima Bank"
+	if parser.BankName() != expected {
+		t.Errorf("expected %q, got %q", expected, parser.BankName())
+	}
+}
+
+func TestOptimaParser_Score(t *testing.T) {
+	parser := NewOptimaParser()
+
+	tests := []struct {
+		name     string
+		content  string
+		expected bool
+	}{
+		{
+			name:     "contains Optima Bank",
+			content:  "Statement from Optima Bank for January",
+			expected: true,
+		},
+		{
+			name:     "contains OptimaBank",
+			content:  "OptimaBank statement",
+			expected: true,
+		},
+		{
+			name:     "contains optimabank.kg",
+			content:  "Visit optimabank.kg for more info",
+			expected: true,
+		},
+		{
+			name:     "no Optima references",
+			content:  "Statement from Another Bank",
+			expected: false,
+		},
+		{
+			name:     "empty content",
+			content:  "",
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := parser.Score(tt.content) > 0
+			if result != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestOptimaParser_Markers(t *testing.T) {
+	parser := NewOptimaParser()
+
+	markers := parser.Markers()
+	if len(markers) == 0 {
+		t.Fatal("expected at least one marker")
+	}
+	for _, m := range markers {
+		if score := parser.Score("statement " + m.Text + " text"); score < m.Weight {
+			t.Errorf("marker %q scored %d, expected at least %d", m.Text, score, m.Weight)
+		}
+	}
+
+	// Callers must not be able to change detection through the returned slice
+	markers[0].Text = "changed"
+	if parser.Markers()[0].Text != "optimabank.kg" {
+		t.Error("Markers returned the parser's internal slice")
+	}
+}
+
+func TestOptimaParser_Parse(t *testing.T) {
+	parser := NewOptimaParser()
+
+	tests := []struct {
+		name             string
+		content          string
+		expectedCount    int
+		expectedAmount   int64
+		expectedCurrency string
+	}{
+		{
+			name:          "empty content",
+			content:       "",
+			expectedCount: 0,
+		},
+		{
+			name: "single transaction",
+			content: `Optima Bank Statement
+15.01.2025
+10:30
+Payment to merchant
+-1 500.00
+KGS
+0
+KGS`,
+			expectedCount:    1,
+			expectedAmount:   -150000,
+			expectedCurrency: "KGS",
+		},
+		{
+			name: "transaction with credit (positive amount)",
+			content: `Optima Bank Statement
+15.01.2025
+10:30
+Incoming transfer
+5 000.00
+KGS
+0
+KGS`,
+			expectedCount:    1,
+			expectedAmount:   500000,
+			expectedCurrency: "KGS",
+		},
+		{
+			name: "multiple transactions",
+			content: `Optima Bank Statement
+15.01.2025
+10:30
+First payment
+-1 000.00
+KGS
+0
+KGS
+15.01.2025
+14:45
+Second payment
+-2 000.00
+KGS
+0
+KGS`,
+			expectedCount: 2,
+		},
+		{
+			name: "skip header lines",
+			content: `Optima Bank Statement
+Date
+Details
+of
+operations
+Operation
+amount
+15.01.2025
+10:30
+Real transaction
+-500.00
+KGS
+0
+KGS`,
+			expectedCount:  1,
+			expectedAmount: -50000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parser.Parse(tt.content)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			transactions := result.Transactions
+			if len(transactions) != tt.expectedCount {
+				t.Errorf("expected %d transactions, got %d", tt.expectedCount, len(transactions))
+			}
+			if tt.expectedCount > 0 && tt.expectedAmount != 0 {
+				if transactions[0].Amount.Minor != tt.expectedAmount {
+					t.Errorf("expected amount %v, got %v", tt.expectedAmount, transactions[0].Amount.Minor)
+				}
+			}
+			if tt.expectedCount > 0 && tt.expectedCurrency != "" {
+				if transactions[0].Amount.Currency != tt.expectedCurrency {
+					t.Errorf("expected currency %v, got %v", tt.expectedCurrency, transactions[0].Amount.Currency)
+				}
+			}
+		})
+	}
+}
+
+func TestOptimaParser_ForeignCurrency(t *testing.T) {
+	parser := NewOptimaParser()
+
+	tests := []struct {
+		name             string
+		content          string
+		expectedAmount   Money
+		expectedOriginal Money
+	}{
+		{
+			name: "USD purchase on a KGS account",
+			content: `Optima Bank Statement
+15.01.2025
+10:30
+Netflix
+-15.00
+USD
+-1 312.50
+KGS
+0
+KGS
+15.01.2025
+11:00
+Local shop
+-200.00
+KGS
+0
+KGS`,
+			expectedAmount:   NewMoney(-131250, "KGS"),
+			expectedOriginal: NewMoney(-1500, "USD"),
+		},
+		{
+			name: "EUR purchase on a USD account",
+			content: `Optima Bank Statement
+15.01.2025
+10:30
+Booking
+-20.00
+EUR
+-21.70
+USD
+0
+USD
+15.01.2025
+11:00
+Amazon
+-5.00
+USD
+0
+USD`,
+			expectedAmount:   NewMoney(-2170, "USD"),
+			expectedOriginal: NewMoney(-2000, "EUR"),
+		},
+		{
+			name: "account-currency purchase has no original amount",
+			content: `Optima Bank Statement
+15.01.2025
+10:30
+Local shop
+-200.00
+KGS
+0
+KGS`,
+			expectedAmount: NewMoney(-20000, "KGS"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parser.Parse(tt.content)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			transactions := result.Transactions
+			if len(transactions) == 0 {
+				t.Fatal("expected at least one transaction")
+			}
+			if transactions[0].Amount != tt.expectedAmount {
+				t.Errorf("expected amount %v, got %v", tt.expectedAmount, transactions[0].Amount)
+			}
+			if transactions[0].OriginalAmount != tt.expectedOriginal {
+				t.Errorf("expected original amount %v, got %v", tt.expectedOriginal, transactions[0].OriginalAmount)
+			}
+		})
+	}
+}
+
+func TestOptimaParser_Fee(t *testing.T) {
+	parser := NewOptimaParser()
+
+	content := `Optima Bank Statement
+15.01.2025
+10:30
+Transfer to another bank
+-1 000.00
+KGS
+20.00
+KGS
+15.01.2025
+11:00
+Local shop
+-200.00
+KGS
+0
+KGS`
+
+	result, err := parser.Parse(content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	transactions := result.Transactions
+	if len(transactions) != 2 {
+		t.Fatalf("expected 2 transactions, got %d", len(transactions))
+	}
+
+	expected := []struct {
+		description string
+		fee         Money
+		gross       Money
+	}{
+		{description: "Transfer to another bank", fee: NewMoney(2000, "KGS"), gross: NewMoney(-102000, "KGS")},
+		{description: "Local shop", fee: Money{}, gross: NewMoney(-20000, "KGS")},
+	}
+	for i, e := range expected {
+		tx := transactions[i]
+		if tx.Description != e.description {
+			t.Errorf("transaction %d: expected description %q, got %q", i, e.description, tx.Description)
+		}
+		if tx.Fee != e.fee {
+			t.Errorf("transaction %d: expected fee %v, got %v", i, e.fee, tx.Fee)
+		}
+		if tx.Gross() != e.gross {
+			t.Errorf("transaction %d: expected gross %v, got %v", i, e.gross, tx.Gross())
+		}
+	}
+}
+
+func TestOptimaParser_Warnings(t *testing.T) {
+	parser := NewOptimaParser()
+
+	content := `Optima Bank Statement
+15.01.2025
+Missing time
+15.01.2025
+10:30
+Payment
+-500.00
+KGS
+0
+KGS`
+
+	result, err := parser.Parse(content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Transactions) != 1 {
+		t.Fatalf("expected 1 transaction, got %d", len(result.Transactions))
+	}
+
+	if len(result.Warnings) != 1 {
+		t.Fatalf("expected 1 warning, got %d", len(result.Warnings))
+	}
+	w := result.Warnings[0]
+	if w.Source.StartLine != 2 || w.Text != "15.01.2025" || w.Reason != "date not followed by a time" {
+		t.Errorf("unexpected warning: %+v", w)
+	}
+
+	expectedLines := LineStats{Consumed: 7, Skipped: 2, Rejected: 1}
+	if result.Lines != expectedLines {
+		t.Errorf("expected line stats %+v, got %+v", expectedLines, result.Lines)
+	}
+}
+
+func TestOptimaParser_SourceLines(t *testing.T) {
+	parser := NewOptimaParser()
+
+	content := `Optima Bank Statement
+15.01.2025
+10:30
+First payment
+-1 000.00
+KGS
+0
+KGS
+
+15.01.2025
+14:45
+Second payment
+continues here
+-2 000.00
+KGS
+0
+KGS`
+
+	result, err := parser.Parse(content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	transactions := result.Transactions
+	if len(transactions) != 2 {
+		t.Fatalf("expected 2 transactions, got %d", len(transactions))
+	}
+
+	expected := []Source{
+		{StartLine: 2, EndLine: 8},
+		{StartLine: 10, EndLine: 17},
+	}
+	for i, src := range expected {
+		if transactions[i].Source != src {
+			t.Errorf("transaction %d: expected source %+v, got %+v", i, src, transactions[i].Source)
+		}
+	}
+}
+
+func TestNormalizeSpaces(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "regular spaces",
+			input:    "hello world",
+			expected: "hello world",
+		},
+		{
+			name:     "non-breaking space",
+			input:    "hello\u00a0world",
+			expected: "hello world",
+		},
+		{
+			name:     "figure space",
+			input:    "1\u2007000",
+			expected: "1 000",
+		},
+		{
+			name:     "narrow no-break space",
+			input:    "1\u202f000",
+			expected: "1 000",
+		},
+		{
+			name:     "mixed spaces",
+			input:    "1\u00a0000\u2007500\u202f00",
+			expected: "1 000 500 00",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := normalizeSpaces(tt.input)
+			if result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestParseOptimaAmount(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected int64
+	}{
+		{
+			name:     "simple amount",
+			input:    "100.00",
+			expected: 10000,
+		},
+		{
+			name:     "negative amount",
+			input:    "-100.00",
+			expected: -10000,
+		},
+		{
+			name:     "amount with space separator",
+			input:    "1 000.00",
+			expected: 100000,
+		},
+		{
+			name:     "large amount with spaces",
+			input:    "1 234 567.89",
+			expected: 123456789,
+		},
+		{
+			name:     "negative with spaces",
+			input:    "-1 965.84",
+			expected: -196584,
+		},
+		{
+			name:     "amount with non-breaking space",
+			input:    "1\u00a0000.00",
+			expected: 100000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parseOptimaAmount(tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
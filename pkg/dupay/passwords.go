@@ -0,0 +1,108 @@
+package dupay
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// maxPromptAttempts is how many times the user is asked for the password of
+// one file before it is given up.
+const maxPromptAttempts = 3
+
+// Passwords supplies the passwords tried on encrypted statements: the one
+// configured for the file, then Password, then whatever Prompt returns.
+type Passwords struct {
+	// Password is tried on every encrypted file.
+	Password string
+	// ByFile maps file names or glob patterns to passwords, as read by
+	// ReadPasswordFile.
+	ByFile map[string]string
+	// Prompt asks for the password of a file. When it is nil, files that no
+	// known password opens are given up instead.
+	Prompt func(path string) (string, error)
+
+	// mu serializes prompts.
+	mu sync.Mutex
+}
+
+// ReadPasswordFile reads a JSON object mapping statement file names or glob
+// patterns, such as "optima-*.pdf", to passwords.
+func ReadPasswordFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	byFile := make(map[string]string)
+	if err := json.Unmarshal(data, &byFile); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for pattern := range byFile {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("%s: invalid pattern %q", path, pattern)
+		}
+	}
+	return byFile, nil
+}
+
+// Lookup returns the password configured for path, matching the path as
+// given, then its base name, then glob patterns such as "optima-*.pdf"
+// against the base name in sorted order.
+func (p *Passwords) Lookup(path string) (string, bool) {
+	if pw, ok := p.ByFile[path]; ok {
+		return pw, true
+	}
+	base := filepath.Base(path)
+	if pw, ok := p.ByFile[base]; ok {
+		return pw, true
+	}
+
+	patterns := make([]string, 0, len(p.ByFile))
+	for pattern := range p.ByFile {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+	for _, pattern := range patterns {
+		if ok, _ := filepath.Match(pattern, base); ok {
+			return p.ByFile[pattern], true
+		}
+	}
+	return "", false
+}
+
+// ForFile returns the password callback for ExtractEncryptedPDF. It offers
+// each known password once, then prompts up to three times, and returns ""
+// when it has nothing left to try.
+func (p *Passwords) ForFile(path string) func() string {
+	var candidates []string
+	if pw, ok := p.Lookup(path); ok && pw != "" {
+		candidates = append(candidates, pw)
+	}
+	if p.Password != "" {
+		candidates = append(candidates, p.Password)
+	}
+
+	prompts := 0
+	return func() string {
+		if len(candidates) > 0 {
+			next := candidates[0]
+			candidates = candidates[1:]
+			return next
+		}
+		if p.Prompt == nil || prompts == maxPromptAttempts {
+			return ""
+		}
+		prompts++
+
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		pw, err := p.Prompt(path)
+		if err != nil {
+			return ""
+		}
+		return pw
+	}
+}
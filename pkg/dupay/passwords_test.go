@@ -0,0 +1,156 @@
+package dupay
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadPasswordFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected map[string]string
+		errMsg   string
+	}{
+		{
+			name:     "names and patterns",
+			data:     `{"mbank-2025-12.pdf": "secret", "optima-*.pdf": "1234"}`,
+			expected: map[string]string{"mbank-2025-12.pdf": "secret", "optima-*.pdf": "1234"},
+		},
+		{name: "empty", data: `{}`, expected: map[string]string{}},
+		{name: "not an object", data: `["secret"]`, errMsg: "cannot unmarshal"},
+		{name: "invalid pattern", data: `{"optima-[.pdf": "1234"}`, errMsg: `invalid pattern "optima-[.pdf"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "passwords.json")
+			if err := os.WriteFile(path, []byte(tt.data), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			byFile, err := ReadPasswordFile(path)
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("expected an error containing %q, got %v", tt.errMsg, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(byFile, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, byFile)
+			}
+		})
+	}
+
+	if _, err := ReadPasswordFile(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestPasswordsLookup(t *testing.T) {
+	p := &Passwords{ByFile: map[string]string{
+		"statements/mbank.pdf": "by-path",
+		"mbank.pdf":            "by-name",
+		"optima-*.pdf":         "optima",
+		"*.pdf":                "any",
+		"empty.pdf":            "",
+	}}
+
+	tests := []struct {
+		path     string
+		expected string
+		found    bool
+	}{
+		{"statements/mbank.pdf", "by-path", true},
+		{"other/mbank.pdf", "by-name", true},
+		{"mbank.pdf", "by-name", true},
+		{"2025/optima-12.pdf", "any", true}, // "*.pdf" sorts before "optima-*.pdf"
+		{"keremet.pdf", "any", true},
+		{"empty.pdf", "", true},
+		{"statement.txt", "", false},
+	}
+
+	for _, tt := range tests {
+		pw, ok := p.Lookup(tt.path)
+		if pw != tt.expected || ok != tt.found {
+			t.Errorf("Lookup(%q): expected %q, %v, got %q, %v", tt.path, tt.expected, tt.found, pw, ok)
+		}
+	}
+
+	p = &Passwords{ByFile: map[string]string{"optima-*.pdf": "optima"}}
+	if pw, ok := p.Lookup("2025/optima-12.pdf"); !ok || pw != "optima" {
+		t.Errorf("expected the pattern to match the base name, got %q, %v", pw, ok)
+	}
+}
+
+func TestPasswordsForFile(t *testing.T) {
+	// tries calls the callback until it gives up, at most 10 times
+	tries := func(next func() string) []string {
+		var got []string
+		for range 10 {
+			pw := next()
+			if pw == "" {
+				break
+			}
+			got = append(got, pw)
+		}
+		return got
+	}
+
+	prompted := 0
+	prompt := func(path string) (string, error) {
+		prompted++
+		return "typed", nil
+	}
+
+	tests := []struct {
+		name      string
+		passwords *Passwords
+		expected  []string
+		prompts   int
+	}{
+		{
+			name:      "configured, then flag, then prompts",
+			passwords: &Passwords{Password: "flag", ByFile: map[string]string{"mbank.pdf": "file"}, Prompt: prompt},
+			expected:  []string{"file", "flag", "typed", "typed", "typed"},
+			prompts:   maxPromptAttempts,
+		},
+		{
+			name:      "no prompt without a terminal",
+			passwords: &Passwords{Password: "flag"},
+			expected:  []string{"flag"},
+		},
+		{
+			name:      "empty configured password is skipped",
+			passwords: &Passwords{ByFile: map[string]string{"mbank.pdf": ""}},
+		},
+		{
+			name: "prompt error gives up",
+			passwords: &Passwords{Prompt: func(string) (string, error) {
+				prompted++
+				return "", errors.New("interrupted")
+			}},
+			prompts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prompted = 0
+			got := tries(tt.passwords.ForFile("statements/mbank.pdf"))
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected passwords %v, got %v", tt.expected, got)
+			}
+			if prompted != tt.prompts {
+				t.Errorf("expected %d prompts, got %d", tt.prompts, prompted)
+			}
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package dupay
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// Document is the extracted text of a PDF, kept per page so transactions can
+// be traced back to the page they were printed on.
+type Document struct {
+	// Pages holds the readable pages in order. Unreadable pages are left out.
+	Pages []Page
+
+	// reader reads the rows of the pages on first use. It is nil once they
+	// are read, and for documents built by hand.
+	reader *pdf.Reader
+}
+
+// Page is the text of a single PDF page.
+type Page struct {
+	// Number is the 1-based page number in the PDF.
+	Number int
+	// Text is the plain text of the page.
+	Text string
+	// Rows holds the page's text by position, from the top of the page down.
+	// Reading positions is much slower than reading text, so ExtractPDF
+	// leaves it empty until Document.Rows is first called. It stays empty
+	// when the layout of the page cannot be read.
+	Rows []Row
+}
+
+// ErrPassword is returned for an encrypted PDF when no password was given or
+// none of the given passwords opens it.
+var ErrPassword = errors.New("encrypted PDF: missing or wrong password")
+
+// ExtractPDF extracts the text of every page of a PDF file.
+// Pages that cannot be read are skipped.
+func ExtractPDF(path string) (*Document, error) {
+	return ExtractEncryptedPDF(path, nil)
+}
+
+// ExtractEncryptedPDF is like ExtractPDF but also opens encrypted PDFs. It
+// calls password for passwords to try until one opens the file or password
+// returns "". PDFs that open without a password never call it; a nil
+// password is treated as one that has none to offer.
+func ExtractEncryptedPDF(path string, password func() string) (*Document, error) {
+	// The document keeps the data to read the layout later
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	r, err := pdf.NewReaderEncrypted(bytes.NewReader(data), int64(len(data)), password)
+	if errors.Is(err, pdf.ErrInvalidPassword) {
+		return nil, ErrPassword
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &Document{reader: r}
+	for i := 1; i <= r.NumPage(); i++ {
+		p := r.Page(i)
+		if p.V.IsNull() {
+			continue
+		}
+		text, err := p.GetPlainText(nil)
+		if err != nil {
+			continue
+		}
+		doc.Pages = append(doc.Pages, Page{Number: i, Text: text})
+	}
+
+	return doc, nil
+}
+
+// ExtractPDFText extracts all text content from a PDF file.
+// Pages are separated by a newline; pages that cannot be read are skipped.
+func ExtractPDFText(path string) (string, error) {
+	doc, err := ExtractPDF(path)
+	if err != nil {
+		return "", err
+	}
+	return doc.Text(), nil
+}
+
+// Text returns the text of all pages, each followed by a newline. This is
+// the content passed to BankParser.Parse.
+func (d *Document) Text() string {
+	var buf strings.Builder
+	for _, p := range d.Pages {
+		buf.WriteString(p.Text)
+		buf.WriteString("\n")
+	}
+	return buf.String()
+}
+
+// Rows returns the rows of all pages in order. This is the input to
+// LayoutParser.ParseLayout.
+func (d *Document) Rows() []Row {
+	d.readLayout()
+	var rows []Row
+	for _, p := range d.Pages {
+		rows = append(rows, p.Rows...)
+	}
+	return rows
+}
+
+// Locate converts a 1-based line number in Text() to a page number and a
+// 1-based line number within that page. It returns zeros for lines outside
+// the document.
+func (d *Document) Locate(line int) (page, pageLine int) {
+	return d.locate(line, func(p Page) int { return strings.Count(p.Text, "\n") + 1 })
+}
+
+// LocateRow converts a 1-based row number in Rows() to a page number and a
+// 1-based row number within that page, like Locate.
+func (d *Document) LocateRow(row int) (page, pageRow int) {
+	d.readLayout()
+	return d.locate(row, func(p Page) int { return len(p.Rows) })
+}
+
+// readLayout fills in the rows of every page the first time they are needed.
+func (d *Document) readLayout() {
+	if d.reader == nil {
+		return
+	}
+	for i := range d.Pages {
+		p := &d.Pages[i]
+		if rows, err := pageRows(d.reader.Page(p.Number), p.Number); err == nil {
+			p.Rows = rows
+		}
+	}
+	d.reader = nil
+}
+
+func (d *Document) locate(line int, count func(Page) int) (page, pageLine int) {
+	offset := 0
+	for _, p := range d.Pages {
+		n := count(p)
+		if line > offset && line <= offset+n {
+			return p.Number, line - offset
+		}
+		offset += n
+	}
+	return 0, 0
+}
+
+// Parse parses the document with parser and attaches the source of every
+// transaction and warning. Parsers that implement LayoutParser read the
+// rows of the document; the plain text is parsed when they find no table.
+func (d *Document) Parse(file string, parser BankParser) (ParseResult, error) {
+	if lp, ok := parser.(LayoutParser); ok {
+		result, err := lp.ParseLayout(d.Rows())
+		if err == nil {
+			d.AttachLayoutResult(file, &result)
+			return result, nil
+		}
+		if !errors.Is(err, ErrNoTable) {
+			return ParseResult{}, err
+		}
+	}
+
+	result, err := parser.Parse(d.Text())
+	if err != nil {
+		return ParseResult{}, err
+	}
+	d.AttachResult(file, &result)
+	return result, nil
+}
+
+// AttachSource fills in the Source of transactions parsed from d.Text():
+// the file path, the page of the first line, and line numbers relative to
+// that page.
+func (d *Document) AttachSource(file string, transactions []Transaction) {
+	for i := range transactions {
+		d.attach(file, &transactions[i].Source, d.Locate)
+	}
+}
+
+// AttachResult fills in the Source of every transaction and warning in a
+// result parsed from d.Text(), like AttachSource.
+func (d *Document) AttachResult(file string, result *ParseResult) {
+	d.attachResult(file, result, d.Locate)
+}
+
+// AttachLayoutResult fills in the Source of every transaction and warning in
+// a result parsed from d.Rows(). Line numbers in the Source count rows.
+func (d *Document) AttachLayoutResult(file string, result *ParseResult) {
+	d.attachResult(file, result, d.LocateRow)
+}
+
+func (d *Document) attachResult(file string, result *ParseResult, locate func(int) (int, int)) {
+	for i := range result.Transactions {
+		d.attach(file, &result.Transactions[i].Source, locate)
+	}
+	for i := range result.Warnings {
+		d.attach(file, &result.Warnings[i].Source, locate)
+	}
+}
+
+func (d *Document) attach(file string, src *Source, locate func(int) (int, int)) {
+	src.File = file
+
+	if src.StartLine == 0 {
+		return
+	}
+	page, start := locate(src.StartLine)
+	src.Page = page
+	src.EndLine = start + src.EndLine - src.StartLine
+	src.StartLine = start
+}
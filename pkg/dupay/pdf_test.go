@@ -0,0 +1,213 @@
+package dupay
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDocumentLocate(t *testing.T) {
+	doc := &Document{
+		Pages: []Page{
+			{Number: 1, Text: "header\nfirst"},
+			{Number: 3, Text: "second\nthird\nfourth"},
+		},
+	}
+
+	tests := []struct {
+		line         int
+		expectedPage int
+		expectedLine int
+	}{
+		{line: 1, expectedPage: 1, expectedLine: 1},
+		{line: 2, expectedPage: 1, expectedLine: 2},
+		{line: 3, expectedPage: 3, expectedLine: 1},
+		{line: 5, expectedPage: 3, expectedLine: 3},
+		{line: 0, expectedPage: 0, expectedLine: 0},
+		{line: 7, expectedPage: 0, expectedLine: 0},
+	}
+
+	for _, tt := range tests {
+		page, line := doc.Locate(tt.line)
+		if page != tt.expectedPage || line != tt.expectedLine {
+			t.Errorf("Locate(%d): expected page %d line %d, got page %d line %d",
+				tt.line, tt.expectedPage, tt.expectedLine, page, line)
+		}
+	}
+}
+
+func TestDocumentAttachSource(t *testing.T) {
+	doc := &Document{
+		Pages: []Page{
+			{Number: 1, Text: "Mbank Statement\n24.12.2025 10:00 Payment - 500,00"},
+			{Number: 2, Text: "24.12.2025 11:00 First line of\ndescription continues here - 700,00"},
+		},
+	}
+
+	result, err := NewMbankParser().Parse(doc.Text())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	transactions := result.Transactions
+	if len(transactions) != 2 {
+		t.Fatalf("expected 2 transactions, got %d", len(transactions))
+	}
+
+	doc.AttachSource("/tmp/statements/mbank.pdf", transactions)
+
+	expected := []Source{
+		{File: "/tmp/statements/mbank.pdf", Page: 1, StartLine: 2, EndLine: 2},
+		{File: "/tmp/statements/mbank.pdf", Page: 2, StartLine: 1, EndLine: 2},
+	}
+	for i, src := range expected {
+		if transactions[i].Source != src {
+			t.Errorf("transaction %d: expected source %+v, got %+v", i, src, transactions[i].Source)
+		}
+	}
+
+	if got := transactions[1].Source.String(); got != "mbank.pdf, page 2, lines 1-2" {
+		t.Errorf("unexpected source string %q", got)
+	}
+}
+
+func TestSourceString(t *testing.T) {
+	tests := []struct {
+		source   Source
+		expected string
+	}{
+		{source: Source{}, expected: ""},
+		{source: Source{File: "a/optima.pdf"}, expected: "optima.pdf"},
+		{source: Source{File: "optima.pdf", Page: 3, StartLine: 7, EndLine: 7}, expected: "optima.pdf, page 3, line 7"},
+		{source: Source{StartLine: 4, EndLine: 9}, expected: "lines 4-9"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.source.String(); got != tt.expected {
+			t.Errorf("expected %q, got %q", tt.expected, got)
+		}
+	}
+}
+
+func TestDocumentLocateRow(t *testing.T) {
+	doc := &Document{
+		Pages: []Page{
+			{Number: 1, Rows: []Row{{Page: 1}, {Page: 1}}},
+			{Number: 2},
+			{Number: 3, Rows: []Row{{Page: 3}}},
+		},
+	}
+
+	tests := []struct {
+		row          int
+		expectedPage int
+		expectedRow  int
+	}{
+		{row: 1, expectedPage: 1, expectedRow: 1},
+		{row: 2, expectedPage: 1, expectedRow: 2},
+		{row: 3, expectedPage: 3, expectedRow: 1},
+		{row: 0, expectedPage: 0, expectedRow: 0},
+		{row: 4, expectedPage: 0, expectedRow: 0},
+	}
+
+	for _, tt := range tests {
+		page, row := doc.LocateRow(tt.row)
+		if page != tt.expectedPage || row != tt.expectedRow {
+			t.Errorf("LocateRow(%d): expected page %d row %d, got page %d row %d",
+				tt.row, tt.expectedPage, tt.expectedRow, page, row)
+		}
+	}
+}
+
+func TestDocumentParse(t *testing.T) {
+	text := "Optima Bank Statement\n15.01.2025\n10:30\nPayment to merchant\n-1 500.00\nKGS\n0\nKGS"
+
+	t.Run("layout", func(t *testing.T) {
+		doc := &Document{
+			Pages: []Page{
+				{Number: 1, Text: text, Rows: []Row{optimaTestHeader}},
+				{Number: 2, Text: "", Rows: []Row{
+					optimaRow(2, "15.01.2025", "Payment to merchant", "-1 500.00 KGS", "0 KGS"),
+					optimaRow(2, "10:30"),
+				}},
+			},
+		}
+
+		result, err := doc.Parse("optima.pdf", NewOptimaParser())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(result.Transactions) != 1 {
+			t.Fatalf("expected 1 transaction, got %d", len(result.Transactions))
+		}
+		expected := Source{File: "optima.pdf", Page: 2, StartLine: 1, EndLine: 2}
+		if result.Transactions[0].Source != expected {
+			t.Errorf("expected source %+v, got %+v", expected, result.Transactions[0].Source)
+		}
+	})
+
+	t.Run("falls back to text without a table", func(t *testing.T) {
+		doc := &Document{Pages: []Page{{Number: 1, Text: text}}}
+
+		result, err := doc.Parse("optima.pdf", NewOptimaParser())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(result.Transactions) != 1 {
+			t.Fatalf("expected 1 transaction, got %d", len(result.Transactions))
+		}
+		expected := Source{File: "optima.pdf", Page: 1, StartLine: 2, EndLine: 8}
+		if result.Transactions[0].Source != expected {
+			t.Errorf("expected source %+v, got %+v", expected, result.Transactions[0].Source)
+		}
+	})
+}
+
+func TestExtractEncryptedPDF(t *testing.T) {
+	// testdata/encrypted.pdf is encrypted with the user password "secret"
+	const path = "testdata/encrypted.pdf"
+
+	t.Run("without a password", func(t *testing.T) {
+		if _, err := ExtractPDF(path); !errors.Is(err, ErrPassword) {
+			t.Errorf("expected ErrPassword, got %v", err)
+		}
+	})
+
+	t.Run("wrong passwords", func(t *testing.T) {
+		passwords := []string{"guess", "another"}
+		var calls int
+		_, err := ExtractEncryptedPDF(path, func() string {
+			if calls == len(passwords) {
+				return ""
+			}
+			calls++
+			return passwords[calls-1]
+		})
+		if !errors.Is(err, ErrPassword) {
+			t.Errorf("expected ErrPassword, got %v", err)
+		}
+		if calls != len(passwords) {
+			t.Errorf("expected every password to be tried, got %d calls", calls)
+		}
+	})
+
+	t.Run("right password", func(t *testing.T) {
+		passwords := []string{"guess", "secret"}
+		doc, err := ExtractEncryptedPDF(path, func() string {
+			next := passwords[0]
+			passwords = passwords[1:]
+			return next
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if text := doc.Text(); !strings.Contains(text, "24.12.2025 10:00 Payment - 500,00") {
+			t.Errorf("expected the decrypted text, got %q", text)
+		}
+
+		// Rows are read on first use, after the file was opened
+		rows := doc.Rows()
+		if len(rows) != 2 || rows[1].Text() != "24.12.2025 10:00 Payment - 500,00" {
+			t.Errorf("expected the decrypted rows, got %+v", rows)
+		}
+	})
+}
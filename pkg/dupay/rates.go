@@ -0,0 +1,247 @@
+package dupay
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultBaseCurrency is the currency exchange rates are quoted against when
+// the rate file does not say otherwise. The National Bank of the Kyrgyz
+// Republic publishes its official rates in KGS.
+const DefaultBaseCurrency = "KGS"
+
+// rateDateLayouts are the accepted date formats in rate files.
+var rateDateLayouts = []string{"2006-01-02", "02.01.2006"}
+
+// ExchangeRates is a table of daily exchange rates against a base currency.
+// A rate is the number of base-currency units for one unit of the currency,
+// e.g. 87.45 for USD against KGS.
+type ExchangeRates struct {
+	// Base is the currency all rates are quoted against.
+	Base  string
+	rates map[string][]dailyRate
+}
+
+// dailyRate is the rate of one currency on one day.
+type dailyRate struct {
+	date time.Time
+	rate float64
+}
+
+// NewExchangeRates returns an empty rate table quoted against base.
+func NewExchangeRates(base string) *ExchangeRates {
+	return &ExchangeRates{Base: base, rates: make(map[string][]dailyRate)}
+}
+
+// Add sets the rate of currency on the given day, replacing any rate already
+// set for that day.
+func (r *ExchangeRates) Add(date time.Time, currency string, rate float64) {
+	day := truncateToDay(date)
+	rates := r.rates[currency]
+	i, found := slices.BinarySearchFunc(rates, day, func(d dailyRate, t time.Time) int {
+		return d.date.Compare(t)
+	})
+	if found {
+		rates[i].rate = rate
+		return
+	}
+	r.rates[currency] = slices.Insert(rates, i, dailyRate{date: day, rate: rate})
+}
+
+// Rate returns the rate of currency in effect on date: the rate for that day
+// or, for weekends and holidays, the most recent earlier one. The base
+// currency always has a rate of 1.
+func (r *ExchangeRates) Rate(currency string, date time.Time) (float64, bool) {
+	if currency == r.Base {
+		return 1, true
+	}
+	rates := r.rates[currency]
+	i, found := slices.BinarySearchFunc(rates, truncateToDay(date), func(d dailyRate, t time.Time) int {
+		return d.date.Compare(t)
+	})
+	if found {
+		return rates[i].rate, true
+	}
+	if i == 0 {
+		return 0, false
+	}
+	return rates[i-1].rate, true
+}
+
+// Convert converts m into currency at the rates in effect on date, rounding
+// to the nearest minor unit. It reports false when either rate is unknown.
+func (r *ExchangeRates) Convert(m Money, currency string, date time.Time) (Money, bool) {
+	if m.Currency == currency {
+		return m, true
+	}
+	from, ok := r.Rate(m.Currency, date)
+	if !ok {
+		return Money{}, false
+	}
+	to, ok := r.Rate(currency, date)
+	if !ok || to == 0 {
+		return Money{}, false
+	}
+	return NewMoney(int64(math.Round(float64(m.Minor)*from/to)), currency), true
+}
+
+// LoadExchangeRates reads a rate table from a .csv or .json file.
+func LoadExchangeRates(path string) (*ExchangeRates, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var rates *ExchangeRates
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".csv":
+		rates, err = ReadExchangeRatesCSV(f)
+	case ".json":
+		rates, err = ReadExchangeRatesJSON(f)
+	default:
+		return nil, fmt.Errorf("unsupported rate file type %q (expected .csv or .json)", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return rates, nil
+}
+
+// ReadExchangeRatesCSV reads rates quoted against DefaultBaseCurrency from a
+// CSV table with a header row naming the date, currency and rate columns,
+// plus an optional nominal column for rates quoted per 10 or 100 units:
+//
+//	date,currency,rate
+//	15.01.2025,USD,87.4500
+func ReadExchangeRatesCSV(r io.Reader) (*ExchangeRates, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("empty rate file")
+		}
+		return nil, err
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"date", "currency", "rate"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("missing %q column", name)
+		}
+	}
+
+	rates := NewExchangeRates(DefaultBaseCurrency)
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return rates, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := cr.FieldPos(0)
+
+		field := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+		if err := rates.addRecord(field("date"), field("currency"), field("rate"), field("nominal")); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+}
+
+// jsonRates is the layout of a JSON rate file.
+type jsonRates struct {
+	Base  string `json:"base"`
+	Rates []struct {
+		Date     string      `json:"date"`
+		Currency string      `json:"currency"`
+		Rate     json.Number `json:"rate"`
+		Nominal  json.Number `json:"nominal"`
+	} `json:"rates"`
+}
+
+// ReadExchangeRatesJSON reads rates from a JSON document such as
+//
+//	{"base": "KGS", "rates": [{"date": "2025-01-15", "currency": "USD", "rate": 87.45}]}
+//
+// The base defaults to DefaultBaseCurrency when omitted.
+func ReadExchangeRatesJSON(r io.Reader) (*ExchangeRates, error) {
+	var doc jsonRates
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, err
+	}
+	base := doc.Base
+	if base == "" {
+		base = DefaultBaseCurrency
+	}
+
+	rates := NewExchangeRates(base)
+	for i, entry := range doc.Rates {
+		if err := rates.addRecord(entry.Date, entry.Currency, entry.Rate.String(), entry.Nominal.String()); err != nil {
+			return nil, fmt.Errorf("rate %d: %w", i+1, err)
+		}
+	}
+	return rates, nil
+}
+
+// addRecord parses and adds one rate from its text fields. An empty nominal
+// means the rate is quoted per unit.
+func (r *ExchangeRates) addRecord(date, currency, rate, nominal string) error {
+	day, err := parseRateDate(date)
+	if err != nil {
+		return err
+	}
+	currency = strings.ToUpper(currency)
+	if currency == "" {
+		return errors.New("missing currency")
+	}
+	value, err := strconv.ParseFloat(strings.ReplaceAll(rate, ",", "."), 64)
+	if err != nil || value <= 0 {
+		return fmt.Errorf("invalid rate %q", rate)
+	}
+	if nominal != "" {
+		n, err := strconv.ParseFloat(nominal, 64)
+		if err != nil || n <= 0 {
+			return fmt.Errorf("invalid nominal %q", nominal)
+		}
+		value /= n
+	}
+	r.Add(day, currency, value)
+	return nil
+}
+
+func parseRateDate(s string) (time.Time, error) {
+	for _, layout := range rateDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", s)
+}
+
+// truncateToDay drops the time of day, keeping the calendar date.
+func truncateToDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
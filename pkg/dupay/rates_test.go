@@ -0,0 +1,127 @@
+package dupay
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExchangeRatesRate(t *testing.T) {
+	rates := NewExchangeRates("KGS")
+	rates.Add(time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), "USD", 87.45)
+	rates.Add(time.Date(2025, 1, 17, 0, 0, 0, 0, time.UTC), "USD", 87.60)
+	rates.Add(time.Date(2025, 1, 16, 0, 0, 0, 0, time.UTC), "USD", 87.50)
+
+	tests := []struct {
+		name     string
+		currency string
+		date     time.Time
+		expected float64
+		ok       bool
+	}{
+		{name: "exact day", currency: "USD", date: time.Date(2025, 1, 16, 14, 30, 0, 0, time.UTC), expected: 87.50, ok: true},
+		{name: "weekend uses last rate", currency: "USD", date: time.Date(2025, 1, 19, 10, 0, 0, 0, time.UTC), expected: 87.60, ok: true},
+		{name: "before first rate", currency: "USD", date: time.Date(2025, 1, 14, 10, 0, 0, 0, time.UTC), ok: false},
+		{name: "base currency", currency: "KGS", date: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), expected: 1, ok: true},
+		{name: "unknown currency", currency: "EUR", date: time.Date(2025, 1, 16, 0, 0, 0, 0, time.UTC), ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, ok := rates.Rate(tt.currency, tt.date)
+			if ok != tt.ok || rate != tt.expected {
+				t.Errorf("expected %v (%v), got %v (%v)", tt.expected, tt.ok, rate, ok)
+			}
+		})
+	}
+}
+
+func TestExchangeRatesConvert(t *testing.T) {
+	day := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	rates := NewExchangeRates("KGS")
+	rates.Add(day, "USD", 87.45)
+	rates.Add(day, "EUR", 90.00)
+
+	tests := []struct {
+		name     string
+		amount   Money
+		currency string
+		expected Money
+	}{
+		{name: "to base", amount: NewMoney(-1500, "USD"), currency: "KGS", expected: NewMoney(-131175, "KGS")},
+		{name: "from base", amount: NewMoney(-131175, "KGS"), currency: "USD", expected: NewMoney(-1500, "USD")},
+		{name: "through base", amount: NewMoney(-1000, "EUR"), currency: "USD", expected: NewMoney(-1029, "USD")},
+		{name: "same currency", amount: NewMoney(-1000, "EUR"), currency: "EUR", expected: NewMoney(-1000, "EUR")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, ok := rates.Convert(tt.amount, tt.currency, day)
+			if !ok || result != tt.expected {
+				t.Errorf("expected %v, got %v (%v)", tt.expected, result, ok)
+			}
+		})
+	}
+
+	if _, ok := rates.Convert(NewMoney(-100, "RUB"), "KGS", day); ok {
+		t.Error("expected conversion without a RUB rate to fail")
+	}
+}
+
+func TestReadExchangeRatesCSV(t *testing.T) {
+	content := `date,currency,rate,nominal
+15.01.2025,USD,87.4500,
+2025-01-15,KZT,16.80,100
+`
+	rates, err := ReadExchangeRatesCSV(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rates.Base != DefaultBaseCurrency {
+		t.Errorf("expected base %q, got %q", DefaultBaseCurrency, rates.Base)
+	}
+
+	day := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	if rate, ok := rates.Rate("USD", day); !ok || rate != 87.45 {
+		t.Errorf("expected USD rate 87.45, got %v (%v)", rate, ok)
+	}
+	if rate, ok := rates.Rate("KZT", day); !ok || rate != 0.168 {
+		t.Errorf("expected KZT rate 0.168, got %v (%v)", rate, ok)
+	}
+}
+
+func TestReadExchangeRatesCSVErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "empty file", content: ""},
+		{name: "missing rate column", content: "date,currency\n15.01.2025,USD\n"},
+		{name: "invalid date", content: "date,currency,rate\n2025/01/15,USD,87.45\n"},
+		{name: "invalid rate", content: "date,currency,rate\n15.01.2025,USD,abc\n"},
+		{name: "negative rate", content: "date,currency,rate\n15.01.2025,USD,-1\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadExchangeRatesCSV(strings.NewReader(tt.content)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestReadExchangeRatesJSON(t *testing.T) {
+	content := `{"base": "USD", "rates": [{"date": "2025-01-15", "currency": "KGS", "rate": 0.0114}]}`
+
+	rates, err := ReadExchangeRatesJSON(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rates.Base != "USD" {
+		t.Errorf("expected base USD, got %q", rates.Base)
+	}
+	if rate, ok := rates.Rate("KGS", time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)); !ok || rate != 0.0114 {
+		t.Errorf("expected KGS rate 0.0114, got %v (%v)", rate, ok)
+	}
+}
@@ -0,0 +1,10 @@
+package dupay
+
+// DefaultParsers returns a new instance of every built-in bank parser,
+// in the order they should be tried.
+func DefaultParsers() []BankParser {
+	return []BankParser{
+		NewOptimaParser(),
+		NewMbankParser(),
+	}
+}
@@ -0,0 +1,86 @@
+package dupay
+
+import (
+	"errors"
+	"sort"
+)
+
+// ErrNoParser is reported for a file when none of the registered parsers
+// recognizes its content.
+var ErrNoParser = errors.New("no parser found for this PDF format")
+
+// Report is the outcome of a duplicate search over a set of statement files.
+type Report struct {
+	// Files lists every processed file in the order it was given.
+	Files []FileResult
+	// Options are the settings the search was run with.
+	Options Options
+	// Duplicates holds the potential duplicates found across all files.
+	Duplicates []DuplicateMatch
+	// Transfers holds the internal transfers found across all files.
+	Transfers []TransferMatch
+}
+
+// FileResult describes how a single statement file was processed.
+type FileResult struct {
+	// Path is the file path as given by the caller.
+	Path string
+	// Bank is the name of the parser that handled the file, empty if none did.
+	Bank string
+	// Score is the detection score of the chosen parser.
+	Score int
+	// Forced is set when the parser was chosen by the caller rather than detected.
+	Forced bool
+	// Candidates lists every parser that recognized the file, best first.
+	Candidates []Candidate
+	// Transactions is the number of transactions parsed from the file.
+	Transactions int
+	// Warnings lists the transactions the parser had to drop.
+	Warnings []ParseWarning
+	// Lines counts how the parser used the file's lines.
+	Lines LineStats
+	// Statement describes the account and period, if the parser extracts
+	// them.
+	Statement Statement
+	// TotalsErr is set when the declared totals don't match the parsed
+	// transactions but the file was processed anyway.
+	TotalsErr error
+	// Err is set when the file could not be read or parsed.
+	Err error
+}
+
+// TotalTransactions returns the number of transactions parsed from all files.
+func (r *Report) TotalTransactions() int {
+	total := 0
+	for _, f := range r.Files {
+		total += f.Transactions
+	}
+	return total
+}
+
+// Groups merges the duplicate matches into groups, one per payment that
+// was charged more than once.
+func (r *Report) Groups() []DuplicateGroup {
+	return GroupDuplicates(r.Duplicates, r.Options)
+}
+
+// TotalDuplicateAmounts returns the sum of the extra charges of all
+// duplicate groups per currency, so a payment found in three statements
+// counts twice rather than once per pair. The result is sorted by currency
+// code.
+func (r *Report) TotalDuplicateAmounts() []Money {
+	sums := make(map[string]int64)
+	for _, g := range r.Groups() {
+		sums[g.Currency] += g.Extra.Minor
+	}
+
+	totals := make([]Money, 0, len(sums))
+	for currency, sum := range sums {
+		totals = append(totals, NewMoney(sum, currency))
+	}
+	sort.Slice(totals, func(i, j int) bool {
+		return totals[i].Currency < totals[j].Currency
+	})
+
+	return totals
+}
@@ -0,0 +1,124 @@
+package dupay
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// transactionCSVHeader lists the columns written for each transaction.
+var transactionCSVHeader = []string{"date_time", "bank", "amount", "currency", "description", "raw_line", "file", "page", "start_line", "end_line", "original_amount", "original_currency", "fee", "account", "merchant"}
+
+// WriteCSV writes one row per duplicate match, with both transactions
+// flattened into tx1_* and tx2_* columns. The group column numbers the
+// duplicate groups, so rows of one payment charged several times share it.
+func WriteCSV(w io.Writer, r *Report) error {
+	cw := csv.NewWriter(w)
+
+	group := make(map[DuplicateMatch]int, len(r.Duplicates))
+	for i, g := range r.Groups() {
+		for _, m := range g.Matches {
+			group[m] = i + 1
+		}
+	}
+
+	header := []string{"match", "kind", "time_diff_seconds", "amount_diff", "currency", "converted", "group", "confidence"}
+	header = append(header, prefixColumns("tx1_", transactionCSVHeader)...)
+	header = append(header, prefixColumns("tx2_", transactionCSVHeader)...)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for i, dup := range r.Duplicates {
+		record := []string{
+			strconv.Itoa(i + 1),
+			string(dup.Kind),
+			strconv.FormatFloat(dup.TimeDiff.Seconds(), 'f', -1, 64),
+			dup.AmountDiff.Decimal(),
+			dup.AmountDiff.Currency,
+			strconv.FormatBool(dup.Conversion != nil),
+			strconv.Itoa(group[dup]),
+			strconv.Itoa(dup.Confidence),
+		}
+		record = append(record, transactionCSVRecord(dup.Transaction1)...)
+		record = append(record, transactionCSVRecord(dup.Transaction2)...)
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteTransactionsCSV writes every transaction as a CSV row, turning parsed
+// statements into a spreadsheet-friendly table.
+func WriteTransactionsCSV(w io.Writer, transactions []Transaction) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write(transactionCSVHeader); err != nil {
+		return err
+	}
+	for _, t := range transactions {
+		if err := cw.Write(transactionCSVRecord(t)); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func transactionCSVRecord(t Transaction) []string {
+	var original, fee string
+	if t.OriginalAmount.Currency != "" {
+		original = t.OriginalAmount.Decimal()
+	}
+	if !t.Fee.IsZero() {
+		fee = t.Fee.Decimal()
+	}
+	return []string{
+		t.DateTime.Format(jsonDateTimeLayout),
+		csvText(t.Bank),
+		t.Amount.Decimal(),
+		t.Amount.Currency,
+		csvText(t.Description),
+		csvText(t.RawLine),
+		csvText(t.Source.File),
+		optionalInt(t.Source.Page),
+		optionalInt(t.Source.StartLine),
+		optionalInt(t.Source.EndLine),
+		original,
+		t.OriginalAmount.Currency,
+		fee,
+		csvText(t.Account),
+		csvText(t.Merchant),
+	}
+}
+
+// csvText escapes text taken from statements for spreadsheets, which run a
+// cell starting with "=", "+", "-", "@", a tab or a carriage return as a
+// formula: such values are prefixed with "'" so they are shown as text.
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func prefixColumns(prefix string, columns []string) []string {
+	result := make([]string, len(columns))
+	for i, c := range columns {
+		result[i] = prefix + c
+	}
+	return result
+}
+
+// optionalInt formats v, leaving the cell empty for the zero "unknown" value.
+func optionalInt(v int) string {
+	if v == 0 {
+		return ""
+	}
+	return strconv.Itoa(v)
+}
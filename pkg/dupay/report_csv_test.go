@@ -0,0 +1,125 @@
+package dupay
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+)
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, sampleReport()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected header and 1 row, got %d records", len(records))
+	}
+
+	header, row := records[0], records[1]
+	if len(header) != len(row) {
+		t.Fatalf("header has %d columns, row has %d", len(header), len(row))
+	}
+
+	columns := make(map[string]string)
+	for i, name := range header {
+		columns[name] = row[i]
+	}
+
+	expected := map[string]string{
+		"match":             "1",
+		"time_diff_seconds": "30",
+		"amount_diff":       "0.50",
+		"tx1_bank":          "Optima Bank",
+		"tx1_amount":        "-100.00",
+		"tx2_bank":          "Mbank",
+		"tx2_amount":        "-100.50",
+		"tx2_date_time":     "2025-01-15T10:30:30",
+	}
+	for name, value := range expected {
+		if columns[name] != value {
+			t.Errorf("expected %s=%q, got %q", name, value, columns[name])
+		}
+	}
+}
+
+func TestWriteTransactionsCSV(t *testing.T) {
+	transactions := []Transaction{
+		{
+			DateTime:    time.Date(2025, 12, 24, 12, 2, 0, 0, time.UTC),
+			Description: `Оплата "Globus", Бишкек`,
+			Amount:      NewMoney(-101800, "KGS"),
+			Bank:        "Mbank",
+		},
+		{
+			DateTime:    time.Date(2025, 12, 24, 14, 30, 0, 0, time.UTC),
+			Description: "Пополнение счета",
+			Amount:      NewMoney(500000, "KGS"),
+			Bank:        "Mbank",
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteTransactionsCSV(&buf, transactions); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("expected header and 2 rows, got %d records", len(records))
+	}
+	if records[1][2] != "-1018.00" {
+		t.Errorf("expected amount -1018.00, got %q", records[1][2])
+	}
+	if records[1][4] != transactions[0].Description {
+		t.Errorf("description was not round-tripped: %q", records[1][4])
+	}
+}
+
+func TestWriteTransactionsCSVEscapesFormulas(t *testing.T) {
+	tests := []struct {
+		description string
+		expected    string
+	}{
+		{"=HYPERLINK(\"http://example.com\")", "'=HYPERLINK(\"http://example.com\")"},
+		{"+996 555 123456", "'+996 555 123456"},
+		{"-Globus", "'-Globus"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"\t=1+1", "'\t=1+1"},
+		{"\r=1+1", "'\r=1+1"},
+		{"Globus", "Globus"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		transactions := []Transaction{{
+			DateTime:    time.Date(2025, 12, 24, 12, 2, 0, 0, time.UTC),
+			Description: tt.description,
+			Amount:      NewMoney(-101800, "KGS"),
+			Bank:        "Mbank",
+		}}
+
+		var buf bytes.Buffer
+		if err := WriteTransactionsCSV(&buf, transactions); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		records, err := csv.NewReader(&buf).ReadAll()
+		if err != nil {
+			t.Fatalf("output is not valid CSV: %v", err)
+		}
+		if records[1][4] != tt.expected {
+			t.Errorf("description %q: expected %q, got %q", tt.description, tt.expected, records[1][4])
+		}
+		if records[1][2] != "-1018.00" {
+			t.Errorf("expected the amount to stay a number, got %q", records[1][2])
+		}
+	}
+}
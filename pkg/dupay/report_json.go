@@ -0,0 +1,404 @@
+package dupay
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// ReportSchemaVersion is the version of the JSON report format written by
+// WriteJSON. It is incremented whenever a field is removed or changes meaning;
+// adding fields does not change the version.
+const ReportSchemaVersion = 5
+
+// jsonDateTimeLayout is used for transaction timestamps. Statements carry no
+// time zone, so times are written as local wall-clock time without an offset.
+const jsonDateTimeLayout = "2006-01-02T15:04:05"
+
+// jsonDateLayout is used for dates without a time of day, such as statement
+// periods.
+const jsonDateLayout = "2006-01-02"
+
+type jsonReport struct {
+	SchemaVersion int            `json:"schema_version"`
+	Settings      jsonSettings   `json:"settings"`
+	Files         []jsonFile     `json:"files"`
+	Duplicates    []jsonMatch    `json:"duplicates"`
+	Groups        []jsonGroup    `json:"duplicate_groups"`
+	Transfers     []jsonTransfer `json:"transfers"`
+	Summary       jsonSummary    `json:"summary"`
+}
+
+type jsonSettings struct {
+	MaxTimeDiff                string  `json:"max_time_diff"`
+	MaxTimeDiffSeconds         float64 `json:"max_time_diff_seconds"`
+	MaxAmountDiff              string  `json:"max_amount_diff"`
+	DetectTransfers            bool    `json:"detect_transfers"`
+	MaxTransferTimeDiff        string  `json:"max_transfer_time_diff"`
+	MaxTransferTimeDiffSeconds float64 `json:"max_transfer_time_diff_seconds"`
+	MaxTransferAmountDiff      string  `json:"max_transfer_amount_diff"`
+	CompareGross               bool    `json:"compare_gross"`
+	OneToOne                   bool    `json:"one_to_one"`
+	MinConfidence              int     `json:"min_confidence"`
+	SameMerchant               bool    `json:"same_merchant"`
+	ExchangeRatesBase          string  `json:"exchange_rates_base,omitempty"`
+	MaxRateDiffPercent         float64 `json:"max_rate_diff_percent,omitempty"`
+}
+
+type jsonFile struct {
+	Path         string          `json:"path"`
+	Parser       string          `json:"parser,omitempty"`
+	Score        int             `json:"score"`
+	Forced       bool            `json:"forced"`
+	Candidates   []jsonCandidate `json:"candidates"`
+	Transactions int             `json:"transactions"`
+	Lines        jsonLineStats   `json:"lines"`
+	Warnings     []jsonWarning   `json:"warnings"`
+	Statement    *jsonStatement  `json:"statement,omitempty"`
+	TotalsError  string          `json:"totals_error,omitempty"`
+	Error        string          `json:"error,omitempty"`
+}
+
+type jsonStatement struct {
+	Account     string      `json:"account,omitempty"`
+	Currency    string      `json:"currency,omitempty"`
+	Holder      string      `json:"holder,omitempty"`
+	PeriodStart string      `json:"period_start,omitempty"`
+	PeriodEnd   string      `json:"period_end,omitempty"`
+	GeneratedAt string      `json:"generated_at,omitempty"`
+	Totals      *jsonTotals `json:"totals,omitempty"`
+}
+
+type jsonTotals struct {
+	OpeningBalance *jsonMoney `json:"opening_balance,omitempty"`
+	ClosingBalance *jsonMoney `json:"closing_balance,omitempty"`
+	TotalDebits    *jsonMoney `json:"total_debits,omitempty"`
+	TotalCredits   *jsonMoney `json:"total_credits,omitempty"`
+}
+
+type jsonLineStats struct {
+	Consumed int `json:"consumed"`
+	Skipped  int `json:"skipped"`
+	Rejected int `json:"rejected"`
+}
+
+type jsonWarning struct {
+	File      string `json:"file,omitempty"`
+	Page      int    `json:"page,omitempty"`
+	StartLine int    `json:"start_line,omitempty"`
+	EndLine   int    `json:"end_line,omitempty"`
+	Text      string `json:"text"`
+	Reason    string `json:"reason"`
+}
+
+type jsonCandidate struct {
+	Parser string `json:"parser"`
+	Score  int    `json:"score"`
+}
+
+type jsonTransaction struct {
+	DateTime         string `json:"date_time"`
+	Description      string `json:"description"`
+	Merchant         string `json:"merchant,omitempty"`
+	Amount           string `json:"amount"`
+	Currency         string `json:"currency"`
+	OriginalAmount   string `json:"original_amount,omitempty"`
+	OriginalCurrency string `json:"original_currency,omitempty"`
+	Fee              string `json:"fee,omitempty"`
+	Bank             string `json:"bank"`
+	Account          string `json:"account,omitempty"`
+	RawLine          string `json:"raw_line"`
+	File             string `json:"file,omitempty"`
+	Page             int    `json:"page,omitempty"`
+	StartLine        int    `json:"start_line,omitempty"`
+	EndLine          int    `json:"end_line,omitempty"`
+}
+
+type jsonMatch struct {
+	Kind            string          `json:"kind"`
+	Transaction1    jsonTransaction `json:"transaction1"`
+	Transaction2    jsonTransaction `json:"transaction2"`
+	TimeDiff        string          `json:"time_diff"`
+	TimeDiffSeconds float64         `json:"time_diff_seconds"`
+	AmountDiff      string          `json:"amount_diff"`
+	Currency        string          `json:"currency"`
+	Conversion      *jsonConversion `json:"conversion,omitempty"`
+	Confidence      int             `json:"confidence"`
+}
+
+type jsonGroup struct {
+	Kind              string            `json:"kind"`
+	Currency          string            `json:"currency"`
+	Transactions      []jsonTransaction `json:"transactions"`
+	Amounts           []string          `json:"amounts"`
+	Matches           []int             `json:"matches"`
+	TimeSpread        string            `json:"time_spread"`
+	TimeSpreadSeconds float64           `json:"time_spread_seconds"`
+	AmountSpread      string            `json:"amount_spread"`
+	Extra             string            `json:"extra"`
+	Confidence        int               `json:"confidence"`
+}
+
+type jsonConversion struct {
+	Currency string `json:"currency"`
+	Amount1  string `json:"amount1"`
+	Amount2  string `json:"amount2"`
+}
+
+type jsonTransactions struct {
+	SchemaVersion int               `json:"schema_version"`
+	Transactions  []jsonTransaction `json:"transactions"`
+}
+
+type jsonMoney struct {
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+type jsonTransfer struct {
+	Debit           jsonTransaction `json:"debit"`
+	Credit          jsonTransaction `json:"credit"`
+	TimeDiff        string          `json:"time_diff"`
+	TimeDiffSeconds float64         `json:"time_diff_seconds"`
+	AmountDiff      string          `json:"amount_diff"`
+}
+
+type jsonSummary struct {
+	Files                 int         `json:"files"`
+	TotalTransactions     int         `json:"total_transactions"`
+	Duplicates            int         `json:"duplicates"`
+	DuplicateGroups       int         `json:"duplicate_groups"`
+	Transfers             int         `json:"transfers"`
+	TotalDuplicateAmounts []jsonMoney `json:"total_duplicate_amounts"`
+}
+
+// WriteJSON writes the report as an indented, versioned JSON document.
+// Amounts are written as decimal strings (e.g. "-1018.00") so they keep
+// their exact value.
+func WriteJSON(w io.Writer, r *Report) error {
+	doc := jsonReport{
+		SchemaVersion: ReportSchemaVersion,
+		Settings: jsonSettings{
+			MaxTimeDiff:                r.Options.MaxTimeDiff.String(),
+			MaxTimeDiffSeconds:         r.Options.MaxTimeDiff.Seconds(),
+			MaxAmountDiff:              FormatAmount(r.Options.MaxAmountDiff),
+			DetectTransfers:            r.Options.DetectTransfers,
+			MaxTransferTimeDiff:        r.Options.MaxTransferTimeDiff.String(),
+			MaxTransferTimeDiffSeconds: r.Options.MaxTransferTimeDiff.Seconds(),
+			MaxTransferAmountDiff:      FormatAmount(r.Options.MaxTransferAmountDiff),
+			CompareGross:               r.Options.CompareGross,
+			OneToOne:                   r.Options.OneToOne,
+			MinConfidence:              r.Options.MinConfidence,
+			SameMerchant:               r.Options.SameMerchant,
+		},
+		Files:      make([]jsonFile, 0, len(r.Files)),
+		Duplicates: make([]jsonMatch, 0, len(r.Duplicates)),
+		Groups:     make([]jsonGroup, 0),
+		Transfers:  make([]jsonTransfer, 0, len(r.Transfers)),
+		Summary: jsonSummary{
+			Files:                 len(r.Files),
+			TotalTransactions:     r.TotalTransactions(),
+			Duplicates:            len(r.Duplicates),
+			Transfers:             len(r.Transfers),
+			TotalDuplicateAmounts: []jsonMoney{},
+		},
+	}
+
+	for _, total := range r.TotalDuplicateAmounts() {
+		doc.Summary.TotalDuplicateAmounts = append(doc.Summary.TotalDuplicateAmounts, newJSONMoney(total))
+	}
+
+	for _, f := range r.Files {
+		jf := jsonFile{
+			Path:         f.Path,
+			Parser:       f.Bank,
+			Score:        f.Score,
+			Forced:       f.Forced,
+			Candidates:   make([]jsonCandidate, 0, len(f.Candidates)),
+			Transactions: f.Transactions,
+			Lines: jsonLineStats{
+				Consumed: f.Lines.Consumed,
+				Skipped:  f.Lines.Skipped,
+				Rejected: f.Lines.Rejected,
+			},
+			Warnings: make([]jsonWarning, 0, len(f.Warnings)),
+		}
+		for _, c := range f.Candidates {
+			jf.Candidates = append(jf.Candidates, jsonCandidate{Parser: c.Parser.BankName(), Score: c.Score})
+		}
+		for _, w := range f.Warnings {
+			jf.Warnings = append(jf.Warnings, jsonWarning{
+				File:      w.Source.File,
+				Page:      w.Source.Page,
+				StartLine: w.Source.StartLine,
+				EndLine:   w.Source.EndLine,
+				Text:      w.Text,
+				Reason:    w.Reason,
+			})
+		}
+		if f.Statement != (Statement{}) {
+			jf.Statement = newJSONStatement(f.Statement)
+		}
+		if f.TotalsErr != nil {
+			jf.TotalsError = f.TotalsErr.Error()
+		}
+		if f.Err != nil {
+			jf.Error = f.Err.Error()
+		}
+		doc.Files = append(doc.Files, jf)
+	}
+
+	if r.Options.Rates != nil {
+		doc.Settings.ExchangeRatesBase = r.Options.Rates.Base
+		doc.Settings.MaxRateDiffPercent = r.Options.MaxRateDiffPercent
+	}
+
+	for _, dup := range r.Duplicates {
+		jm := jsonMatch{
+			Kind:            string(dup.Kind),
+			Transaction1:    newJSONTransaction(dup.Transaction1),
+			Transaction2:    newJSONTransaction(dup.Transaction2),
+			TimeDiff:        dup.TimeDiff.String(),
+			TimeDiffSeconds: dup.TimeDiff.Seconds(),
+			AmountDiff:      dup.AmountDiff.Decimal(),
+			Currency:        dup.AmountDiff.Currency,
+			Confidence:      dup.Confidence,
+		}
+		if c := dup.Conversion; c != nil {
+			jm.Conversion = &jsonConversion{
+				Currency: c.Currency,
+				Amount1:  c.Amount1.Decimal(),
+				Amount2:  c.Amount2.Decimal(),
+			}
+		}
+		doc.Duplicates = append(doc.Duplicates, jm)
+	}
+
+	// Groups refer to their matches by position in duplicates
+	matchIndex := make(map[DuplicateMatch]int, len(r.Duplicates))
+	for i, dup := range r.Duplicates {
+		matchIndex[dup] = i
+	}
+	for _, g := range r.Groups() {
+		jg := jsonGroup{
+			Kind:              string(g.Kind),
+			Currency:          g.Currency,
+			Transactions:      make([]jsonTransaction, 0, len(g.Transactions)),
+			Amounts:           make([]string, 0, len(g.Amounts)),
+			Matches:           make([]int, 0, len(g.Matches)),
+			TimeSpread:        g.TimeSpread.String(),
+			TimeSpreadSeconds: g.TimeSpread.Seconds(),
+			AmountSpread:      g.AmountSpread.Decimal(),
+			Extra:             g.Extra.Decimal(),
+			Confidence:        g.Confidence,
+		}
+		for k, t := range g.Transactions {
+			jg.Transactions = append(jg.Transactions, newJSONTransaction(t))
+			var amount string
+			if g.Amounts[k].Currency != "" {
+				amount = g.Amounts[k].Decimal()
+			}
+			jg.Amounts = append(jg.Amounts, amount)
+		}
+		for _, m := range g.Matches {
+			jg.Matches = append(jg.Matches, matchIndex[m])
+		}
+		doc.Groups = append(doc.Groups, jg)
+	}
+	doc.Summary.DuplicateGroups = len(doc.Groups)
+
+	for _, tr := range r.Transfers {
+		doc.Transfers = append(doc.Transfers, jsonTransfer{
+			Debit:           newJSONTransaction(tr.Debit),
+			Credit:          newJSONTransaction(tr.Credit),
+			TimeDiff:        tr.TimeDiff.String(),
+			TimeDiffSeconds: tr.TimeDiff.Seconds(),
+			AmountDiff:      tr.AmountDiff.Decimal(),
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+// WriteTransactionsJSON writes all transactions as a versioned JSON document
+// using the same transaction layout as WriteJSON.
+func WriteTransactionsJSON(w io.Writer, transactions []Transaction) error {
+	doc := jsonTransactions{
+		SchemaVersion: ReportSchemaVersion,
+		Transactions:  make([]jsonTransaction, 0, len(transactions)),
+	}
+	for _, t := range transactions {
+		doc.Transactions = append(doc.Transactions, newJSONTransaction(t))
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+func newJSONTransaction(t Transaction) jsonTransaction {
+	jt := jsonTransaction{
+		DateTime:    t.DateTime.Format(jsonDateTimeLayout),
+		Description: t.Description,
+		Merchant:    t.Merchant,
+		Amount:      t.Amount.Decimal(),
+		Currency:    t.Amount.Currency,
+		Bank:        t.Bank,
+		Account:     t.Account,
+		RawLine:     t.RawLine,
+		File:        t.Source.File,
+		Page:        t.Source.Page,
+		StartLine:   t.Source.StartLine,
+		EndLine:     t.Source.EndLine,
+	}
+	if t.OriginalAmount.Currency != "" {
+		jt.OriginalAmount = t.OriginalAmount.Decimal()
+		jt.OriginalCurrency = t.OriginalAmount.Currency
+	}
+	if !t.Fee.IsZero() {
+		jt.Fee = t.Fee.Decimal()
+	}
+	return jt
+}
+
+func newJSONMoney(m Money) jsonMoney {
+	return jsonMoney{
+		Amount:   m.Decimal(),
+		Currency: m.Currency,
+	}
+}
+
+func newJSONStatement(s Statement) *jsonStatement {
+	js := &jsonStatement{
+		Account:  s.Account,
+		Currency: s.Currency,
+		Holder:   s.Holder,
+	}
+	if !s.PeriodStart.IsZero() {
+		js.PeriodStart = s.PeriodStart.Format(jsonDateLayout)
+		js.PeriodEnd = s.PeriodEnd.Format(jsonDateLayout)
+	}
+	if !s.GeneratedAt.IsZero() {
+		js.GeneratedAt = s.GeneratedAt.Format(jsonDateTimeLayout)
+	}
+
+	t := s.Totals
+	if t.IsZero() {
+		return js
+	}
+	optional := func(m Money) *jsonMoney {
+		if m.Currency == "" {
+			return nil
+		}
+		jm := newJSONMoney(m)
+		return &jm
+	}
+	js.Totals = &jsonTotals{
+		OpeningBalance: optional(t.OpeningBalance),
+		ClosingBalance: optional(t.ClosingBalance),
+		TotalDebits:    optional(t.TotalDebits),
+		TotalCredits:   optional(t.TotalCredits),
+	}
+	return js
+}
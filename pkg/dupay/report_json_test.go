@@ -0,0 +1,113 @@
+package dupay
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func sampleReport() *Report {
+	baseTime := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	return &Report{
+		Files: []FileResult{
+			{Path: "optima.pdf", Bank: "Optima Bank", Transactions: 3},
+			{Path: "mbank.pdf", Bank: "Mbank", Transactions: 2},
+			{Path: "other.pdf", Err: ErrNoParser},
+		},
+		Duplicates: []DuplicateMatch{
+			{
+				Transaction1: Transaction{Bank: "Optima Bank", DateTime: baseTime, Amount: NewMoney(-10000, "KGS"), Description: "Coffee"},
+				Transaction2: Transaction{Bank: "Mbank", DateTime: baseTime.Add(30 * time.Second), Amount: NewMoney(-10050, "KGS"), Description: "Coffee"},
+				TimeDiff:     30 * time.Second,
+				AmountDiff:   NewMoney(50, "KGS"),
+				Confidence:   65,
+			},
+		},
+		Transfers: []TransferMatch{
+			{
+				Debit:      Transaction{Bank: "Optima Bank", DateTime: baseTime.Add(time.Hour), Amount: NewMoney(-500000, "KGS")},
+				Credit:     Transaction{Bank: "Mbank", DateTime: baseTime.Add(time.Hour + 2*time.Minute), Amount: NewMoney(500000, "KGS")},
+				TimeDiff:   2 * time.Minute,
+				AmountDiff: NewMoney(0, "KGS"),
+			},
+		},
+		Options: DefaultOptions(),
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, sampleReport()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var doc jsonReport
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+
+	if doc.SchemaVersion != ReportSchemaVersion {
+		t.Errorf("expected schema version %d, got %d", ReportSchemaVersion, doc.SchemaVersion)
+	}
+	if len(doc.Files) != 3 {
+		t.Fatalf("expected 3 files, got %d", len(doc.Files))
+	}
+	if doc.Files[0].Parser != "Optima Bank" || doc.Files[0].Transactions != 3 {
+		t.Errorf("unexpected first file: %+v", doc.Files[0])
+	}
+	if doc.Files[2].Error != ErrNoParser.Error() {
+		t.Errorf("expected no-parser error, got %q", doc.Files[2].Error)
+	}
+	if len(doc.Duplicates) != 1 {
+		t.Fatalf("expected 1 duplicate, got %d", len(doc.Duplicates))
+	}
+
+	dup := doc.Duplicates[0]
+	if dup.TimeDiffSeconds != 30 || dup.AmountDiff != "0.50" || dup.Currency != "KGS" || dup.Confidence != 65 {
+		t.Errorf("unexpected match differences: %+v", dup)
+	}
+	if dup.Transaction1.DateTime != "2025-01-15T10:30:00" {
+		t.Errorf("unexpected date_time %q", dup.Transaction1.DateTime)
+	}
+	if dup.Transaction2.Bank != "Mbank" {
+		t.Errorf("expected second transaction from Mbank, got %q", dup.Transaction2.Bank)
+	}
+	if len(doc.Transfers) != 1 || doc.Transfers[0].Credit.Amount != "5000.00" || doc.Transfers[0].TimeDiffSeconds != 120 {
+		t.Errorf("unexpected transfers: %+v", doc.Transfers)
+	}
+	if !doc.Settings.DetectTransfers || doc.Settings.MaxTransferTimeDiff != "10m0s" {
+		t.Errorf("unexpected settings: %+v", doc.Settings)
+	}
+	if doc.Summary.TotalTransactions != 5 || doc.Summary.Duplicates != 1 || doc.Summary.Transfers != 1 {
+		t.Errorf("unexpected summary: %+v", doc.Summary)
+	}
+	expectedTotals := []jsonMoney{{Amount: "-100.25", Currency: "KGS"}}
+	if !reflect.DeepEqual(doc.Summary.TotalDuplicateAmounts, expectedTotals) {
+		t.Errorf("expected total duplicate amounts %v, got %v", expectedTotals, doc.Summary.TotalDuplicateAmounts)
+	}
+	if doc.Duplicates[0].Transaction2.Amount != "-100.50" {
+		t.Errorf("expected exact amount -100.50, got %q", doc.Duplicates[0].Transaction2.Amount)
+	}
+}
+
+func TestWriteJSONEmptyReport(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, &Report{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var raw map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &raw); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+
+	// Empty lists must be arrays, not null, so consumers can iterate safely
+	for _, key := range []string{"files", "duplicates", "transfers"} {
+		if _, ok := raw[key].([]any); !ok {
+			t.Errorf("expected %q to be an array, got %v", key, raw[key])
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package dupay
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReportTotalDuplicateAmounts(t *testing.T) {
+	tx := func(line int, amount Money) Transaction {
+		return Transaction{Amount: amount, Source: Source{StartLine: line}}
+	}
+	pair := func(t1, t2 Transaction) DuplicateMatch {
+		return DuplicateMatch{
+			Transaction1: t1,
+			Transaction2: t2,
+			AmountDiff:   amountDiff(t1.Amount, t2.Amount),
+		}
+	}
+	foreign := DuplicateMatch{
+		Transaction1: Transaction{Amount: NewMoney(-131250, "KGS"), OriginalAmount: NewMoney(-1000, "USD")},
+		Transaction2: Transaction{Amount: NewMoney(-135000, "KGS"), OriginalAmount: NewMoney(-1000, "USD")},
+		AmountDiff:   NewMoney(0, "USD"),
+	}
+
+	a, b, c := tx(1, NewMoney(-10000, "KGS")), tx(2, NewMoney(-10000, "KGS")), tx(3, NewMoney(-10030, "KGS"))
+	r := &Report{
+		Duplicates: []DuplicateMatch{
+			// One payment found in three statements
+			pair(a, b),
+			pair(b, c),
+			pair(a, c),
+			pair(tx(4, NewMoney(-1999, "USD")), tx(5, NewMoney(-2000, "USD"))),
+			foreign,
+		},
+	}
+
+	// KGS: two extra charges at the average of -100.10, not three pairs.
+	// The foreign pair was compared in USD, so it counts towards USD.
+	expected := []Money{NewMoney(-20020, "KGS"), NewMoney(-3000, "USD")}
+	if result := r.TotalDuplicateAmounts(); !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected %v, got %v", expected, result)
+	}
+}
@@ -0,0 +1,245 @@
+package dupay
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// WriteText writes the report in the human-readable format printed by the CLI.
+func WriteText(w io.Writer, r *Report) error {
+	p := &textPrinter{w: w}
+
+	for _, f := range r.Files {
+		p.printf("Processing: %s\n", filepath.Base(f.Path))
+		switch {
+		case errors.Is(f.Err, ErrNoParser):
+			p.printf("  Warning: No parser found for this PDF format\n")
+		case errors.Is(f.Err, ErrAmbiguousParser):
+			p.printf("  Warning: Ambiguous format, matched %s; choose one with -bank\n", formatCandidates(f.Candidates))
+		case f.Err != nil:
+			p.printf("  Error: %v\n", f.Err)
+		case f.Forced:
+			p.printf("  Parser: %s (forced)\n", f.Bank)
+			p.printf("  Found %d transactions\n", f.Transactions)
+		default:
+			p.printf("  Detected: %s (score %d)\n", f.Bank, f.Score)
+			if len(f.Candidates) > 1 {
+				p.printf("  Also matched: %s\n", formatCandidates(f.Candidates[1:]))
+			}
+			p.printf("  Found %d transactions\n", f.Transactions)
+		}
+		if f.Err == nil {
+			if s := formatStatement(f.Statement); s != "" {
+				p.printf("  Statement: %s\n", s)
+			}
+		}
+		if f.TotalsErr != nil {
+			p.printf("  Warning: %v\n", f.TotalsErr)
+		}
+		if len(f.Warnings) > 0 {
+			p.printf("  Warning: %d operation(s) could not be parsed; see 'dupay parse -diagnose'\n", len(f.Warnings))
+		}
+	}
+
+	p.printf("\nTotal transactions: %d\n", r.TotalTransactions())
+
+	if r.Options.DetectTransfers {
+		p.printf("Looking for internal transfers (time diff <= %v, amount diff <= %s)...\n",
+			r.Options.MaxTransferTimeDiff, FormatAmount(r.Options.MaxTransferAmountDiff))
+		writeTransfersText(p, r.Transfers)
+	}
+
+	amounts := "amount"
+	if r.Options.CompareGross {
+		amounts = "amount including fees"
+	}
+	p.printf("Looking for duplicates (time diff <= %v, %s diff <= %s)...\n", r.Options.MaxTimeDiff, amounts, FormatAmount(r.Options.MaxAmountDiff))
+	if r.Options.Rates != nil {
+		p.printf("Comparing other currencies in %s at daily exchange rates (difference <= %g%%)...\n", r.Options.Rates.Base, r.Options.MaxRateDiffPercent)
+	}
+	if r.Options.OneToOne {
+		p.printf("Pairing each debit with at most one counterpart per account...\n")
+	}
+	if r.Options.SameMerchant {
+		p.printf("Pairing only debits of the same merchant...\n")
+	}
+	if r.Options.MinConfidence > 0 {
+		p.printf("Keeping duplicates with confidence >= %d%%...\n", r.Options.MinConfidence)
+	}
+	p.printf("\n")
+
+	groups := r.Groups()
+	if len(groups) == 0 {
+		p.printf("No potential duplicates found.\n")
+		return p.err
+	}
+
+	p.printf("Found %d potential duplicate(s), most likely first:\n\n", len(groups))
+
+	for i, g := range groups {
+		var notes []string
+		if len(g.Transactions) > 2 {
+			notes = append(notes, fmt.Sprintf("%d charges", len(g.Transactions)))
+		}
+		if g.Kind == DoubleCharge {
+			notes = append(notes, "double charge in "+filepath.Base(g.Transactions[0].Source.File))
+		}
+		if len(notes) > 0 {
+			p.printf("=== Duplicate #%d (%s) ===\n", i+1, strings.Join(notes, ", "))
+		} else {
+			p.printf("=== Duplicate #%d ===\n", i+1)
+		}
+
+		converted := slices.ContainsFunc(g.Matches, func(m DuplicateMatch) bool { return m.Conversion != nil })
+		p.printf("Confidence: %d%%\n", g.Confidence)
+		p.printf("Time difference: %v\n", g.TimeSpread)
+		if converted {
+			p.printf("Amount difference: %s (converted at daily exchange rates)\n", g.AmountSpread)
+		} else {
+			p.printf("Amount difference: %s\n", g.AmountSpread)
+		}
+		if len(g.Transactions) > 2 {
+			p.printf("Charged in excess: %s\n", g.Extra)
+		}
+		p.printf("\n")
+
+		for k, t := range g.Transactions {
+			// Show the compared amount only when the transaction doesn't
+			// already show it as its amount or original amount
+			amount := g.Amounts[k]
+			if amount.Currency == t.OriginalAmount.Currency {
+				amount = Money{}
+			}
+			if k > 0 {
+				p.printf("\n")
+			}
+			p.printf("Transaction %d (%s):\n", k+1, accountLabel(t))
+			p.printf("  Date/Time: %s\n", t.DateTime.Format("02.01.2006 15:04"))
+			p.printf("  Amount: %s\n", formatTransactionAmount(t, amount))
+			p.printf("  Description: %s\n", truncateString(t.Description, 80))
+			if t.Merchant != "" {
+				p.printf("  Merchant: %s\n", t.Merchant)
+			}
+			p.printf("  Source: %s\n", t.Source)
+		}
+		p.printf("%s\n", strings.Repeat("-", 60))
+	}
+
+	p.printf("\n")
+	for _, total := range r.TotalDuplicateAmounts() {
+		p.printf("Total potential duplicate amount: %s\n", total)
+	}
+
+	return p.err
+}
+
+// writeTransfersText lists internal transfers, one line per side.
+func writeTransfersText(p *textPrinter, transfers []TransferMatch) {
+	if len(transfers) == 0 {
+		p.printf("No internal transfers found.\n\n")
+		return
+	}
+
+	p.printf("Found %d internal transfer(s), excluded from duplicates:\n", len(transfers))
+	for i, tr := range transfers {
+		p.printf("  %d. %s  %s -> %s  %s\n", i+1,
+			tr.Debit.DateTime.Format("02.01.2006 15:04"),
+			accountLabel(tr.Debit),
+			accountLabel(tr.Credit),
+			tr.Debit.Amount.Abs())
+		p.printf("     from %s\n", tr.Debit.Source)
+		p.printf("     to   %s\n", tr.Credit.Source)
+	}
+	p.printf("\n")
+}
+
+// formatStatement summarizes what a statement shows about its account, e.g.
+// "Mbank 1030********3456, KGS, holder ИВАНОВА АЙГУЛЬ, 01.12.2025 - 31.12.2025".
+// It returns "" when the statement shows nothing beyond its bank.
+func formatStatement(s Statement) string {
+	var parts []string
+	if s.Account != "" {
+		parts = append(parts, s.Label())
+	}
+	if s.Currency != "" {
+		parts = append(parts, s.Currency)
+	}
+	if s.Holder != "" {
+		parts = append(parts, "holder "+s.Holder)
+	}
+	if !s.PeriodStart.IsZero() {
+		parts = append(parts, s.PeriodStart.Format("02.01.2006")+" - "+s.PeriodEnd.Format("02.01.2006"))
+	}
+	if !s.GeneratedAt.IsZero() {
+		parts = append(parts, "generated "+s.GeneratedAt.Format("02.01.2006 15:04"))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatCandidates lists parsers with their scores, e.g. "Mbank (60), Optima Bank (60)".
+func formatCandidates(candidates []Candidate) string {
+	parts := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		parts = append(parts, fmt.Sprintf("%s (%d)", c.Parser.BankName(), c.Score))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// textPrinter remembers the first write error so the report can be written
+// without checking every line.
+type textPrinter struct {
+	w   io.Writer
+	err error
+}
+
+func (p *textPrinter) printf(format string, args ...any) {
+	if p.err != nil {
+		return
+	}
+	_, p.err = fmt.Fprintf(p.w, format, args...)
+}
+
+// formatTransactionAmount formats the account-currency amount, followed by
+// the original amount for foreign-currency transactions, the fee, and the
+// converted amount, if any, when it is in another currency.
+func formatTransactionAmount(t Transaction, converted Money) string {
+	s := t.Amount.String()
+	if t.OriginalAmount.Currency != "" {
+		s += fmt.Sprintf(" (original %s)", t.OriginalAmount)
+	}
+	if !t.Fee.IsZero() {
+		s += fmt.Sprintf(" (fee %s)", t.Fee)
+	}
+	if converted.Currency != "" && converted.Currency != t.Amount.Currency {
+		s += fmt.Sprintf(" (converted %s)", converted)
+	}
+	return s
+}
+
+// truncateString shortens s to maxLen characters, counting runes rather than
+// bytes so Cyrillic text is neither cut mid-character nor padded short.
+func truncateString(s string, maxLen int) string {
+	runes := []rune(s)
+	if len(runes) <= maxLen {
+		return s
+	}
+	return string(runes[:maxLen-3]) + "..."
+}
+
+// WriteTransactionsText writes one aligned line per transaction.
+func WriteTransactionsText(w io.Writer, transactions []Transaction) error {
+	p := &textPrinter{w: w}
+	for _, t := range transactions {
+		p.printf("%s  %-12s %16s  %-60s  %s\n",
+			t.DateTime.Format("02.01.2006 15:04"),
+			t.Bank,
+			t.Amount,
+			truncateString(t.Description, 60),
+			t.Source)
+	}
+	return p.err
+}
@@ -0,0 +1,59 @@
+package dupay
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestTruncateString(t *testing.T) {
+	tests := []struct {
+		s        string
+		maxLen   int
+		expected string
+	}{
+		{"Globus", 10, "Globus"},
+		{"Оплата", 6, "Оплата"},
+		{"Оплата товаров и услуг", 10, "Оплата ..."},
+		{"Payment to Globus", 10, "Payment..."},
+	}
+
+	for _, tt := range tests {
+		got := truncateString(tt.s, tt.maxLen)
+		if got != tt.expected {
+			t.Errorf("truncateString(%q, %d): expected %q, got %q", tt.s, tt.maxLen, tt.expected, got)
+		}
+		if !utf8.ValidString(got) {
+			t.Errorf("truncateString(%q, %d) cut a character in half: %q", tt.s, tt.maxLen, got)
+		}
+	}
+}
+
+func TestWriteTransactionsTextAlignsCyrillic(t *testing.T) {
+	dateTime := time.Date(2025, 12, 24, 10, 0, 0, 0, time.UTC)
+	transactions := []Transaction{
+		{DateTime: dateTime, Bank: "Mbank", Amount: NewMoney(-101800, "KGS"), Description: "Payment to Globus", Source: Source{File: "a.pdf"}},
+		{DateTime: dateTime, Bank: "Optima Bank", Amount: NewMoney(-101800, "KGS"), Description: strings.Repeat("Оплата товаров и услуг: ГЛОБУС ", 3), Source: Source{File: "b.pdf"}},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteTransactionsText(&buf, transactions); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d", len(lines))
+	}
+	if !utf8.ValidString(lines[1]) {
+		t.Errorf("the description was cut mid-character: %q", lines[1])
+	}
+	// The source column starts at the same character on both lines
+	first := utf8.RuneCountInString(lines[0][:strings.Index(lines[0], "a.pdf")])
+	second := utf8.RuneCountInString(lines[1][:strings.Index(lines[1], "b.pdf")])
+	if first != second {
+		t.Errorf("expected the source columns to line up, got offsets %d and %d:\n%s", first, second, buf.String())
+	}
+}
@@ -0,0 +1,70 @@
+package dupay
+
+import (
+	"strings"
+	"time"
+)
+
+// Statement describes the statement a parser read: whose account it is and
+// which period it covers. Fields the statement does not show are left zero.
+type Statement struct {
+	// Bank is the name of the bank that issued the statement.
+	Bank string
+	// Account is the account or card number, masked so that only the first
+	// and last four digits remain (e.g. "1030********3456").
+	Account string
+	// Currency is the account's currency.
+	Currency string
+	// Holder is the account holder's name as printed on the statement.
+	Holder string
+	// PeriodStart and PeriodEnd are the first and last day covered.
+	PeriodStart, PeriodEnd time.Time
+	// GeneratedAt is when the bank generated the statement.
+	GeneratedAt time.Time
+	// Totals holds the totals and balances the statement declares.
+	Totals StatementTotals
+}
+
+// Label names the statement's account for reports, e.g.
+// "Mbank 1030********3456", falling back to the bank name alone.
+func (s Statement) Label() string {
+	if s.Account == "" {
+		return s.Bank
+	}
+	return s.Bank + " " + s.Account
+}
+
+// MaskAccount hides the middle of an account or card number, keeping the
+// first and last four digits. Spaces and dashes are dropped; numbers of eight
+// digits or fewer keep only the last four.
+func MaskAccount(number string) string {
+	var digits []byte
+	for i := 0; i < len(number); i++ {
+		if c := number[i]; c >= '0' && c <= '9' {
+			digits = append(digits, c)
+		}
+	}
+	if len(digits) <= 4 {
+		return string(digits)
+	}
+
+	keepStart := 4
+	if len(digits) <= 8 {
+		keepStart = 0
+	}
+	return string(digits[:keepStart]) + strings.Repeat("*", len(digits)-keepStart-4) + string(digits[len(digits)-4:])
+}
+
+// accountLabel names the account a transaction came from for reports.
+func accountLabel(t Transaction) string {
+	return Statement{Bank: t.Bank, Account: t.Account}.Label()
+}
+
+// sameAccount reports whether two transactions come from the same account:
+// the same bank and, when both statements show one, the same account number.
+func sameAccount(t1, t2 Transaction) bool {
+	if t1.Bank != t2.Bank {
+		return false
+	}
+	return t1.Account == "" || t2.Account == "" || t1.Account == t2.Account
+}
@@ -0,0 +1,47 @@
+package dupay
+
+import "testing"
+
+func TestMaskAccount(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "1030120000123456", expected: "1030********3456"},
+		{input: "1030 1200 0012 3456", expected: "1030********3456"},
+		{input: "4169-5800-0000-1234", expected: "4169********1234"},
+		{input: "12345678", expected: "****5678"},
+		{input: "1234", expected: "1234"},
+		{input: "", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if result := MaskAccount(tt.input); result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestSameAccount(t *testing.T) {
+	tests := []struct {
+		name     string
+		t1, t2   Transaction
+		expected bool
+	}{
+		{name: "different banks", t1: Transaction{Bank: "Mbank"}, t2: Transaction{Bank: "Optima Bank"}, expected: false},
+		{name: "same bank, accounts unknown", t1: Transaction{Bank: "Mbank"}, t2: Transaction{Bank: "Mbank"}, expected: true},
+		{name: "same bank, one account unknown", t1: Transaction{Bank: "Mbank", Account: "****1111"}, t2: Transaction{Bank: "Mbank"}, expected: true},
+		{name: "same account", t1: Transaction{Bank: "Mbank", Account: "****1111"}, t2: Transaction{Bank: "Mbank", Account: "****1111"}, expected: true},
+		{name: "different accounts", t1: Transaction{Bank: "Mbank", Account: "****1111"}, t2: Transaction{Bank: "Mbank", Account: "****2222"}, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := sameAccount(tt.t1, tt.t2); result != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
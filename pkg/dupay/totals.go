@@ -0,0 +1,97 @@
+package dupay
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StatementTotals are the sums and balances a statement declares about
+// itself. Fields the statement does not declare are left zero; a zero Money
+// has an empty Currency.
+type StatementTotals struct {
+	// OpeningBalance and ClosingBalance are the account balances at the
+	// start and end of the period.
+	OpeningBalance, ClosingBalance Money
+	// TotalDebits is the declared sum of all outgoing transactions, as a
+	// non-negative value.
+	TotalDebits Money
+	// TotalCredits is the declared sum of all incoming transactions.
+	TotalCredits Money
+}
+
+// IsZero reports whether the statement declared nothing.
+func (t StatementTotals) IsZero() bool {
+	return t == StatementTotals{}
+}
+
+// Verifiable reports whether the statement declares anything CheckTotals
+// can verify.
+func (t StatementTotals) Verifiable() bool {
+	balances := t.OpeningBalance.Currency != "" && t.ClosingBalance.Currency != ""
+	return balances || t.TotalDebits.Currency != "" || t.TotalCredits.Currency != ""
+}
+
+// TotalsMismatch is one declared figure that the parsed transactions don't
+// add up to.
+type TotalsMismatch struct {
+	// Name describes the figure, e.g. "total debits".
+	Name string
+	// Declared is the value printed on the statement.
+	Declared Money
+	// Parsed is the value computed from the parsed transactions.
+	Parsed Money
+}
+
+// TotalsMismatchError is returned by CheckTotals when extraction missed or
+// double-counted rows.
+type TotalsMismatchError struct {
+	Mismatches []TotalsMismatch
+}
+
+func (e *TotalsMismatchError) Error() string {
+	parts := make([]string, 0, len(e.Mismatches))
+	for _, m := range e.Mismatches {
+		parts = append(parts, fmt.Sprintf("%s declared %s, parsed %s", m.Name, m.Declared, m.Parsed))
+	}
+	return "statement totals do not match the parsed transactions: " + strings.Join(parts, "; ")
+}
+
+// CheckTotals verifies the parsed transactions against the totals the
+// statement declares: the sums of debits and credits, and the closing
+// balance reached from the opening balance. Figures the statement does not
+// declare are not checked. Balances include fees, since they are deducted
+// from the account.
+func CheckTotals(totals StatementTotals, transactions []Transaction) error {
+	var mismatches []TotalsMismatch
+	check := func(name string, declared Money, parsed int64) {
+		if declared.Currency == "" || declared.Minor == parsed {
+			return
+		}
+		mismatches = append(mismatches, TotalsMismatch{
+			Name:     name,
+			Declared: declared,
+			Parsed:   NewMoney(parsed, declared.Currency),
+		})
+	}
+
+	var debits, credits, balance int64
+	for _, t := range transactions {
+		if t.Amount.IsNegative() {
+			debits -= t.Amount.Minor
+		} else {
+			credits += t.Amount.Minor
+		}
+		balance += t.Gross().Minor
+	}
+
+	check("total debits", totals.TotalDebits, debits)
+	check("total credits", totals.TotalCredits, credits)
+	if totals.OpeningBalance.Currency != "" {
+		check("closing balance", totals.ClosingBalance, totals.OpeningBalance.Minor+balance)
+	}
+
+	if len(mismatches) > 0 {
+		return &TotalsMismatchError{Mismatches: mismatches}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package dupay
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCheckTotals(t *testing.T) {
+	transactions := []Transaction{
+		{Amount: NewMoney(-101800, "KGS")},
+		{Amount: NewMoney(-50000, "KGS"), Fee: NewMoney(1000, "KGS")},
+		{Amount: NewMoney(500000, "KGS")},
+	}
+
+	tests := []struct {
+		name       string
+		totals     StatementTotals
+		mismatches []TotalsMismatch
+	}{
+		{
+			name:   "nothing declared",
+			totals: StatementTotals{},
+		},
+		{
+			name: "all totals match",
+			totals: StatementTotals{
+				OpeningBalance: NewMoney(100000, "KGS"),
+				ClosingBalance: NewMoney(447200, "KGS"),
+				TotalDebits:    NewMoney(151800, "KGS"),
+				TotalCredits:   NewMoney(500000, "KGS"),
+			},
+		},
+		{
+			name:   "missed debit",
+			totals: StatementTotals{TotalDebits: NewMoney(171800, "KGS"), TotalCredits: NewMoney(500000, "KGS")},
+			mismatches: []TotalsMismatch{
+				{Name: "total debits", Declared: NewMoney(171800, "KGS"), Parsed: NewMoney(151800, "KGS")},
+			},
+		},
+		{
+			name:   "closing balance without fees",
+			totals: StatementTotals{OpeningBalance: NewMoney(100000, "KGS"), ClosingBalance: NewMoney(448200, "KGS")},
+			mismatches: []TotalsMismatch{
+				{Name: "closing balance", Declared: NewMoney(448200, "KGS"), Parsed: NewMoney(447200, "KGS")},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckTotals(tt.totals, transactions)
+			if tt.mismatches == nil {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var mismatch *TotalsMismatchError
+			if !errors.As(err, &mismatch) {
+				t.Fatalf("expected TotalsMismatchError, got %v", err)
+			}
+			if !reflect.DeepEqual(mismatch.Mismatches, tt.mismatches) {
+				t.Errorf("expected %+v, got %+v", tt.mismatches, mismatch.Mismatches)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package dupay
+
+import (
+	"sort"
+	"time"
+)
+
+// FindTransfers pairs debits in one bank with credits of the same amount in
+// another bank, i.e. money moved between the user's own accounts.
+// Parameters:
+//   - transactions: all transactions from all banks
+//   - maxTimeDiff: maximum time between the debit and the credit (e.g., 10 minutes)
+//   - maxAmountDiff: maximum amount difference in minor units of the pair's currency
+//
+// Every transaction is part of at most one transfer. When a debit could be
+// paired with several credits (or the other way around), the closest pairs in
+// time are taken first. Transfers are returned in the order of their debits.
+func FindTransfers(transactions []Transaction, maxTimeDiff time.Duration, maxAmountDiff int64) []TransferMatch {
+	transactions = deduplicateTransactions(transactions)
+
+	var transfers []TransferMatch
+	for _, p := range findTransferPairs(transactions, maxTimeDiff, maxAmountDiff) {
+		transfers = append(transfers, newTransferMatch(transactions[p.i], transactions[p.j]))
+	}
+
+	return transfers
+}
+
+func newTransferMatch(debit, credit Transaction) TransferMatch {
+	return TransferMatch{
+		Debit:      debit,
+		Credit:     credit,
+		TimeDiff:   absDuration(credit.DateTime.Sub(debit.DateTime)),
+		AmountDiff: amountDiff(debit.Amount, credit.Amount),
+	}
+}
+
+// transferCandidate is a debit and a credit that could be one transfer.
+type transferCandidate struct {
+	debit, credit int
+	timeDiff      time.Duration
+	amountDiff    int64
+}
+
+// findTransferPairs returns transfers as (debit, credit) index pairs into
+// transactions, sorted by debit index.
+func findTransferPairs(transactions []Transaction, maxTimeDiff time.Duration, maxAmountDiff int64) []indexPair {
+	candidates := findTransferCandidates(transactions, maxTimeDiff, maxAmountDiff)
+
+	// Take the closest pairs first so each transaction is used at most once
+	sort.Slice(candidates, func(a, b int) bool {
+		ca, cb := candidates[a], candidates[b]
+		if ca.timeDiff != cb.timeDiff {
+			return ca.timeDiff < cb.timeDiff
+		}
+		if ca.amountDiff != cb.amountDiff {
+			return ca.amountDiff < cb.amountDiff
+		}
+		if ca.debit != cb.debit {
+			return ca.debit < cb.debit
+		}
+		return ca.credit < cb.credit
+	})
+
+	used := make(map[int]bool)
+	var pairs []indexPair
+	for _, c := range candidates {
+		if used[c.debit] || used[c.credit] {
+			continue
+		}
+		used[c.debit] = true
+		used[c.credit] = true
+		pairs = append(pairs, indexPair{i: c.debit, j: c.credit})
+	}
+
+	sort.Slice(pairs, func(a, b int) bool {
+		return pairs[a].i < pairs[b].i
+	})
+
+	return pairs
+}
+
+// findTransferCandidates returns every debit and credit in different accounts
+// that are close enough in time and amount to be a transfer, as indexes into
+// transactions.
+func findTransferCandidates(transactions []Transaction, maxTimeDiff time.Duration, maxAmountDiff int64) []transferCandidate {
+	// Sort every transaction by time so candidates can be found with a sweep
+	order := make([]int, len(transactions))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return transactions[order[a]].DateTime.Before(transactions[order[b]].DateTime)
+	})
+
+	var candidates []transferCandidate
+	for a := 0; a < len(order); a++ {
+		t1 := transactions[order[a]]
+		for b := a + 1; b < len(order); b++ {
+			t2 := transactions[order[b]]
+			if t2.DateTime.Sub(t1.DateTime) > maxTimeDiff {
+				break
+			}
+
+			// A transfer is one debit and one credit in different accounts
+			if sameAccount(t1, t2) || t1.Amount.Currency != t2.Amount.Currency {
+				continue
+			}
+			if t1.Amount.IsNegative() == t2.Amount.IsNegative() || t1.Amount.IsZero() || t2.Amount.IsZero() {
+				continue
+			}
+
+			diff := amountDiff(t1.Amount, t2.Amount).Minor
+			if diff > maxAmountDiff {
+				continue
+			}
+
+			debit, credit := order[a], order[b]
+			if !t1.Amount.IsNegative() {
+				debit, credit = credit, debit
+			}
+			candidates = append(candidates, transferCandidate{
+				debit:      debit,
+				credit:     credit,
+				timeDiff:   t2.DateTime.Sub(t1.DateTime),
+				amountDiff: diff,
+			})
+		}
+	}
+	return candidates
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
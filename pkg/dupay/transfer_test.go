@@ -0,0 +1,122 @@
+package dupay
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFindTransfers(t *testing.T) {
+	baseTime := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		transactions []Transaction
+		expected     int
+	}{
+		{
+			name:         "empty input",
+			transactions: []Transaction{},
+			expected:     0,
+		},
+		{
+			name: "debit and credit in different banks",
+			transactions: []Transaction{
+				{Bank: "Optima Bank", DateTime: baseTime, Amount: NewMoney(-500000, "KGS")},
+				{Bank: "Mbank", DateTime: baseTime.Add(3 * time.Minute), Amount: NewMoney(500000, "KGS")},
+			},
+			expected: 1,
+		},
+		{
+			name: "credit recorded before debit",
+			transactions: []Transaction{
+				{Bank: "Mbank", DateTime: baseTime, Amount: NewMoney(500000, "KGS")},
+				{Bank: "Optima Bank", DateTime: baseTime.Add(time.Minute), Amount: NewMoney(-500000, "KGS")},
+			},
+			expected: 1,
+		},
+		{
+			name: "same bank - not a transfer",
+			transactions: []Transaction{
+				{Bank: "Mbank", DateTime: baseTime, Amount: NewMoney(-500000, "KGS")},
+				{Bank: "Mbank", DateTime: baseTime, Amount: NewMoney(500000, "KGS")},
+			},
+			expected: 0,
+		},
+		{
+			name: "two debits - not a transfer",
+			transactions: []Transaction{
+				{Bank: "Optima Bank", DateTime: baseTime, Amount: NewMoney(-500000, "KGS")},
+				{Bank: "Mbank", DateTime: baseTime, Amount: NewMoney(-500000, "KGS")},
+			},
+			expected: 0,
+		},
+		{
+			name: "outside time window",
+			transactions: []Transaction{
+				{Bank: "Optima Bank", DateTime: baseTime, Amount: NewMoney(-500000, "KGS")},
+				{Bank: "Mbank", DateTime: baseTime.Add(time.Hour), Amount: NewMoney(500000, "KGS")},
+			},
+			expected: 0,
+		},
+		{
+			name: "different amounts",
+			transactions: []Transaction{
+				{Bank: "Optima Bank", DateTime: baseTime, Amount: NewMoney(-500000, "KGS")},
+				{Bank: "Mbank", DateTime: baseTime, Amount: NewMoney(490000, "KGS")},
+			},
+			expected: 0,
+		},
+		{
+			name: "different currencies",
+			transactions: []Transaction{
+				{Bank: "Optima Bank", DateTime: baseTime, Amount: NewMoney(-500000, "KGS")},
+				{Bank: "Mbank", DateTime: baseTime, Amount: NewMoney(500000, "USD")},
+			},
+			expected: 0,
+		},
+		{
+			name: "one credit for two debits",
+			transactions: []Transaction{
+				{Bank: "Optima Bank", DateTime: baseTime, Amount: NewMoney(-500000, "KGS")},
+				{Bank: "Demir Bank", DateTime: baseTime.Add(time.Minute), Amount: NewMoney(-500000, "KGS")},
+				{Bank: "Mbank", DateTime: baseTime.Add(2 * time.Minute), Amount: NewMoney(500000, "KGS")},
+			},
+			expected: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := FindTransfers(tt.transactions, 10*time.Minute, 100)
+			if len(result) != tt.expected {
+				t.Errorf("expected %d transfers, got %d", tt.expected, len(result))
+			}
+		})
+	}
+}
+
+func TestFindTransfersPrefersClosestPair(t *testing.T) {
+	baseTime := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	transactions := []Transaction{
+		{Bank: "Optima Bank", DateTime: baseTime, Amount: NewMoney(-500000, "KGS"), Description: "far"},
+		{Bank: "Optima Bank", DateTime: baseTime.Add(4 * time.Minute), Amount: NewMoney(-500000, "KGS"), Description: "near"},
+		{Bank: "Mbank", DateTime: baseTime.Add(5 * time.Minute), Amount: NewMoney(500000, "KGS")},
+	}
+
+	result := FindTransfers(transactions, 10*time.Minute, 100)
+	if len(result) != 1 {
+		t.Fatalf("expected 1 transfer, got %d", len(result))
+	}
+
+	transfer := result[0]
+	if transfer.Debit.Description != "near" {
+		t.Errorf("expected the closest debit to be paired, got %q", transfer.Debit.Description)
+	}
+	if transfer.TimeDiff != time.Minute {
+		t.Errorf("expected time diff 1m, got %v", transfer.TimeDiff)
+	}
+	if transfer.Credit.Bank != "Mbank" || transfer.Debit.Bank != "Optima Bank" {
+		t.Errorf("unexpected sides: debit %q, credit %q", transfer.Debit.Bank, transfer.Credit.Bank)
+	}
+}
@@ -0,0 +1,173 @@
+package dupay
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Transaction represents a single bank transaction extracted from a PDF statement.
+type Transaction struct {
+	// DateTime is the date and time when the transaction occurred.
+	DateTime time.Time
+	// Description contains the transaction details/memo from the bank statement.
+	Description string
+	// Merchant is the merchant name normalized from Description, e.g.
+	// "GLOBUS", so the same shop compares equal across banks. It is empty
+	// when the description names no merchant. See NormalizeMerchant.
+	Merchant string
+	// Amount is the exact transaction value in the account's currency.
+	// Negative for debits (outgoing), positive for credits (incoming).
+	Amount Money
+	// OriginalAmount is the value in the currency the payment was made in,
+	// for foreign-currency transactions. It is zero (with an empty Currency)
+	// when the payment was made in the account's currency.
+	OriginalAmount Money
+	// Fee is the commission the bank charged for the transaction, as a
+	// non-negative value in the account's currency. It is not included in
+	// Amount, and is zero when the statement has no fee column.
+	Fee Money
+	// Bank is the name of the bank this transaction came from.
+	Bank string
+	// Account is the masked account number of the statement, empty if the
+	// statement doesn't show one. See Statement.Account.
+	Account string
+	// RawLine contains the original text from the PDF for debugging purposes.
+	RawLine string
+	// Source describes where the transaction was read from.
+	Source Source
+}
+
+// Gross returns the total effect of the transaction on the account balance:
+// Amount with the fee deducted.
+func (t Transaction) Gross() Money {
+	return t.Amount.Sub(t.Fee)
+}
+
+// Source describes where in a statement a transaction was read from.
+//
+// Parsers set StartLine and EndLine as 1-based line numbers in the content
+// they were given, or row numbers for LayoutParser.ParseLayout.
+// Document.AttachSource then fills in File and Page and makes the line
+// numbers relative to that page.
+type Source struct {
+	// File is the path of the statement file.
+	File string
+	// Page is the 1-based PDF page the transaction starts on, 0 if unknown.
+	Page int
+	// StartLine is the first line of the transaction, 0 if unknown.
+	StartLine int
+	// EndLine is the last line of the transaction. It is counted from the same
+	// origin as StartLine, so it runs past the end of Page when a row
+	// continues on the next page.
+	EndLine int
+}
+
+// String formats the source for display, e.g. "mbank.pdf, page 2, lines 14-15".
+func (s Source) String() string {
+	var parts []string
+	if s.File != "" {
+		parts = append(parts, filepath.Base(s.File))
+	}
+	if s.Page > 0 {
+		parts = append(parts, fmt.Sprintf("page %d", s.Page))
+	}
+	switch {
+	case s.StartLine == 0:
+	case s.EndLine > s.StartLine:
+		parts = append(parts, fmt.Sprintf("lines %d-%d", s.StartLine, s.EndLine))
+	default:
+		parts = append(parts, fmt.Sprintf("line %d", s.StartLine))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// BankParser defines the interface for parsing bank-specific PDF statement formats.
+// Implement this interface to add support for a new bank.
+type BankParser interface {
+	// Parse extracts transactions from the PDF text content, with warnings
+	// for text that looked like a transaction but had to be dropped and
+	// counts of the lines used. The error is reserved for content that
+	// cannot be parsed at all.
+	Parse(content string) (ParseResult, error)
+	// BankName returns the human-readable name of the bank.
+	BankName() string
+	// ID returns a short lowercase identifier used to select the parser by hand.
+	ID() string
+	// Score returns how confident the parser is that it can handle the given
+	// PDF content, from 0 (not this bank) to MaxScore.
+	Score(content string) int
+	// Markers returns the weighted text fragments Score looks for, for display.
+	Markers() []Marker
+}
+
+// LayoutParser is implemented by parsers that can read a statement's table
+// from the position of its text, which keeps columns such as amounts and
+// fees apart.
+type LayoutParser interface {
+	BankParser
+	// ParseLayout extracts transactions from the rows of all pages, in the
+	// form Document.Rows returns them. Source line numbers count rows. It
+	// returns ErrNoTable when the rows hold no table the parser recognizes.
+	ParseLayout(rows []Row) (ParseResult, error)
+}
+
+// MatchKind tells how the two transactions of a DuplicateMatch are related.
+type MatchKind string
+
+const (
+	// CrossBank is the same payment charged to two different accounts,
+	// usually at different banks.
+	CrossBank MatchKind = "cross-bank"
+	// DoubleCharge is the same payment charged twice within one statement.
+	DoubleCharge MatchKind = "double-charge"
+)
+
+// DuplicateMatch represents a potential duplicate payment, either across
+// different banks or repeated within one statement.
+type DuplicateMatch struct {
+	// Kind tells whether the match is across banks or within one statement.
+	Kind MatchKind
+	// Transaction1 is the first transaction in the potential duplicate pair.
+	Transaction1 Transaction
+	// Transaction2 is the second transaction in the potential duplicate pair.
+	Transaction2 Transaction
+	// TimeDiff is the absolute time difference between the two transactions.
+	TimeDiff time.Duration
+	// AmountDiff is the absolute difference in amounts between the two transactions,
+	// in their shared currency or, for converted pairs, in Conversion.Currency.
+	AmountDiff Money
+	// Conversion is set when the transactions share no currency and were
+	// compared after converting both amounts with an exchange-rate table.
+	Conversion *Conversion
+	// Confidence scores from 0 to 100 how likely the pair is one payment
+	// charged twice, from how close the times and amounts are and how
+	// similar the merchants are.
+	// With transfer detection enabled, pairs whose debits could also be
+	// transfers to another of the user's accounts score lower.
+	Confidence int
+}
+
+// Conversion records how a cross-currency pair was compared.
+type Conversion struct {
+	// Currency is the currency both amounts were converted into.
+	Currency string
+	// Amount1 and Amount2 are the converted amounts of Transaction1 and
+	// Transaction2.
+	Amount1, Amount2 Money
+}
+
+// TransferMatch represents money moved between the user's own accounts: a debit
+// in one bank and the matching credit in another.
+type TransferMatch struct {
+	// Debit is the outgoing side of the transfer.
+	Debit Transaction
+	// Credit is the incoming side of the transfer.
+	Credit Transaction
+	// TimeDiff is the absolute time difference between the two transactions.
+	TimeDiff time.Duration
+	// AmountDiff is the absolute difference in amounts between the two transactions,
+	// in their shared currency.
+	AmountDiff Money
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/rasulov-emirlan/dupay/pkg/dupay"
+)
+
+// loader extracts and parses statement files.
+type loader struct {
+	// parsers is set by load from definitions.
+	parsers     []dupay.BankParser
+	definitions parserDefinitions
+	// banks forces a parser for specific files, keyed by path or base name.
+	banks bankFlag
+	// ignoreTotals processes files whose declared totals don't match the
+	// parsed transactions instead of rejecting them.
+	ignoreTotals bool
+	// passwords opens encrypted files.
+	passwords *passwords
+	// jobs is the number of files processed in parallel.
+	jobs int
+}
+
+func newLoader() *loader {
+	return &loader{
+		banks:     bankFlag{},
+		passwords: newPasswords(),
+		jobs:      runtime.NumCPU(),
+	}
+}
+
+// registerFlags adds the flags shared by every command that reads statements.
+func (l *loader) registerFlags(fs *flag.FlagSet) {
+	fs.Var(l.banks, "bank", "Force a parser for a file, as `file.pdf=id` (repeatable; see 'dupay banks' for IDs)")
+	fs.BoolVar(&l.ignoreTotals, "ignore-totals", false, "Process statements whose declared totals don't match the parsed transactions")
+	fs.IntVar(&l.jobs, "jobs", l.jobs, "Number of statements to read in parallel")
+	l.definitions.registerFlags(fs)
+	l.passwords.registerFlags(fs)
+}
+
+// load extracts and parses every file with up to l.jobs workers and returns
+// the statements in the order of paths. Files that cannot be processed carry
+// their error in the result; the returned error is for the loader's own
+// configuration, or ctx's error when loading was canceled.
+func (l *loader) load(ctx context.Context, paths []string) ([]dupay.StatementFile, error) {
+	if l.jobs < 1 {
+		return nil, fmt.Errorf("-jobs must be at least 1")
+	}
+	parsers, err := l.definitions.load()
+	if err != nil {
+		return nil, err
+	}
+	l.parsers = parsers
+	if err := l.passwords.load(); err != nil {
+		return nil, err
+	}
+
+	return dupay.LoadStatements(ctx, paths, l.jobs, func(path string) dupay.StatementFile {
+		result, transactions := l.processFile(path)
+		return dupay.StatementFile{Result: result, Transactions: transactions}
+	})
+}
+
+// allTransactions concatenates the transactions of all statements.
+func allTransactions(statements []dupay.StatementFile) []dupay.Transaction {
+	var transactions []dupay.Transaction
+	for _, s := range statements {
+		transactions = append(transactions, s.Transactions...)
+	}
+	return transactions
+}
+
+// fileResults returns the per-file results of all statements.
+func fileResults(statements []dupay.StatementFile) []dupay.FileResult {
+	results := make([]dupay.FileResult, 0, len(statements))
+	for _, s := range statements {
+		results = append(results, s.Result)
+	}
+	return results
+}
+
+// processFile extracts and parses a single PDF with the forced parser for
+// the file or, failing that, the highest-scoring one.
+func (l *loader) processFile(path string) (dupay.FileResult, []dupay.Transaction) {
+	result := dupay.FileResult{Path: path}
+
+	// Extract text from PDF
+	doc, err := dupay.ExtractEncryptedPDF(path, l.passwords.ForFile(path))
+	if errors.Is(err, dupay.ErrPassword) {
+		result.Err = fmt.Errorf("reading PDF: %w (use -password or -password-file)", err)
+		return result, nil
+	}
+	if err != nil {
+		result.Err = fmt.Errorf("reading PDF: %w", err)
+		return result, nil
+	}
+	content := doc.Text()
+
+	detection := dupay.DetectParser(l.parsers, content)
+	result.Candidates = detection.Candidates
+
+	var parser dupay.BankParser
+	if id, ok := l.banks.lookup(path); ok {
+		parser = dupay.ParserByID(l.parsers, id)
+		if parser == nil {
+			result.Err = fmt.Errorf("unknown bank %q (see 'dupay banks')", id)
+			return result, nil
+		}
+		result.Forced = true
+		result.Score = parser.Score(content)
+	} else {
+		best, err := detection.Best()
+		if err != nil {
+			result.Err = err
+			return result, nil
+		}
+		parser = best.Parser
+		result.Score = best.Score
+	}
+
+	result.Bank = parser.BankName()
+
+	// Parse transactions
+	parsed, err := doc.Parse(path, parser)
+	if err != nil {
+		result.Err = fmt.Errorf("parsing: %w", err)
+		return result, nil
+	}
+
+	// A statement that doesn't add up means rows were missed or counted twice
+	result.Statement = parsed.Statement
+	if err := dupay.CheckTotals(parsed.Statement.Totals, parsed.Transactions); err != nil {
+		if !l.ignoreTotals {
+			result.Err = fmt.Errorf("%w (use -ignore-totals to process it anyway)", err)
+			return result, nil
+		}
+		result.TotalsErr = err
+	}
+
+	result.Transactions = len(parsed.Transactions)
+	result.Warnings = parsed.Warnings
+	result.Lines = parsed.Lines
+	return result, parsed.Transactions
+}
+
+// bankFlag is a repeatable flag.Value mapping files to parser IDs.
+type bankFlag map[string]string
+
+func (b bankFlag) String() string {
+	pairs := make([]string, 0, len(b))
+	for file, id := range b {
+		pairs = append(pairs, file+"="+id)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (b bankFlag) Set(s string) error {
+	file, id, ok := strings.Cut(s, "=")
+	if !ok || file == "" || id == "" {
+		return fmt.Errorf("expected file.pdf=id, got %q", s)
+	}
+	b[file] = id
+	return nil
+}
+
+// lookup returns the parser ID forced for path, matching either the path as
+// given or its base name.
+func (b bankFlag) lookup(path string) (string, bool) {
+	if id, ok := b[path]; ok {
+		return id, true
+	}
+	id, ok := b[filepath.Base(path)]
+	return id, ok
+}
+
+// amountFlag is a flag.Value holding an exact amount in minor units.
+type amountFlag int64
+
+func (a *amountFlag) String() string {
+	return dupay.FormatAmount(int64(*a))
+}
+
+func (a *amountFlag) Set(s string) error {
+	minor, err := dupay.ParseAmount(s)
+	if err != nil {
+		return err
+	}
+	if minor < 0 {
+		return fmt.Errorf("amount must not be negative")
+	}
+	*a = amountFlag(minor)
+	return nil
+}